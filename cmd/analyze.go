@@ -0,0 +1,73 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package cmd
+
+import (
+	"github.com/soner3/flora/internal/app"
+	"github.com/soner3/flora/internal/scanner"
+	"github.com/spf13/cobra"
+)
+
+var analyzeInputDir string
+var analyzeProfile string
+var analyzeTags []string
+var analyzeGOOS string
+var analyzeGOARCH string
+
+// analyzeCmd groups the commands that inspect the dependency graph
+var analyzeCmd = &cobra.Command{
+	Use:   "analyze",
+	Short: "Inspects the Flora dependency graph",
+}
+
+// unusedCmd represents the analyze unused command
+var unusedCmd = &cobra.Command{
+	Use:   "unused",
+	Short: "Lists components that are never consumed",
+	Long: `Walks the resolved dependency graph from the declared roots and lists every
+component, configuration method, provider and slice or map binding that is
+never consumed, together with its position.
+
+Roots are the components the application reads itself: those tagged 'expose',
+module roots, components with 'bind=', request scoped components and components
+implementing flora.Starter. '--tags', '--goos' and '--goarch' select the scanned
+files like they do for 'flora generate'.`,
+	Example: `  # Report the dead providers of the current module
+  flora analyze unused
+
+  # Analyze the 'prod' profile of the 'internal' directory
+  flora analyze unused -i ./internal --profile prod
+
+  # Analyze the files built for windows
+  flora analyze unused --goos windows`,
+	SilenceUsage: true,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		return validateScanFlags(analyzeInputDir, analyzeProfile, analyzeTags)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return app.RunAnalyzeUnused(analyzeInputDir, scanner.Options{Profile: analyzeProfile, Tags: analyzeTags, GOOS: analyzeGOOS, GOARCH: analyzeGOARCH}, cmd.OutOrStdout())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(analyzeCmd)
+	analyzeCmd.AddCommand(unusedCmd)
+	unusedCmd.Flags().StringVarP(&analyzeInputDir, "input", "i", ".", "Input directory to scan")
+	unusedCmd.Flags().StringVarP(&analyzeProfile, "profile", "p", "", "Active profile; components of other profiles are ignored")
+	unusedCmd.Flags().StringSliceVar(&analyzeTags, "tags", nil, "Comma-separated build tags used for scanning")
+	unusedCmd.Flags().StringVar(&analyzeGOOS, "goos", "", "Target GOOS used for scanning")
+	unusedCmd.Flags().StringVar(&analyzeGOARCH, "goarch", "", "Target GOARCH used for scanning")
+}
@@ -17,15 +17,20 @@ package cmd
 
 import (
 	"log/slog"
-	"os"
 
 	"github.com/soner3/flora/internal/app"
 	"github.com/soner3/flora/internal/errs"
+	"github.com/soner3/flora/internal/scanner"
 	"github.com/spf13/cobra"
 )
 
 var inputDir string
 var outputDir string
+var profile string
+var tags []string
+var goos string
+var goarch string
+var expose string
 
 // generateCmd represents the generate command
 var generateCmd = &cobra.Command{
@@ -36,13 +41,28 @@ var generateCmd = &cobra.Command{
 It resolves the dependency graph, validates missing or duplicate providers, 
 and uses Google Wire under the hood to generate a reflection-free, type-safe DI container.
 
-The resulting 'flora_container.go' will be placed in your specified output directory.`,
+The resulting 'flora_container.go' will be placed in your specified output directory.
+With '--profile', only components of that profile (and those without any profile)
+are wired, and the result is written to 'flora_container.<profile>.go' behind a
+build constraint of the same name. '--tags', '--goos' and '--goarch' select the
+scanned files like 'go build' does, and the container carries the same constraint.
+With '--expose roots-only', only the components nothing else depends on (and
+those tagged 'expose') become container fields.`,
 	Example: `  # Scan current directory and generate container in the 'flora' folder (defaults)
   flora generate
 
   # Scan specific directory and output to the 'cmd/server' package
   flora generate -i ./internal -o ./cmd/server
   
+  # Generate the container for the 'dev' profile (flora_container.dev.go, built with -tags dev)
+  flora generate --profile dev
+
+  # Scan files behind the 'integration' tag for linux (flora_container.integration.linux.go)
+  flora generate --tags integration --goos linux
+
+  # Only expose the entry points of the graph on the container
+  flora generate --expose roots-only
+
   # Using the alias
   flora gen -i ./pkg/services`,
 	SilenceUsage: true,
@@ -50,19 +70,19 @@ The resulting 'flora_container.go' will be placed in your specified output direc
 		log := slog.With("pkg", "cmd")
 		log.Debug("Validating flags", "input", inputDir, "output", outputDir)
 
-		info, err := os.Stat(inputDir)
-		if err != nil {
-			return errs.Wrap(err, "invalid directory provided for flag 'input': %s (directory does not exist)", inputDir)
+		if err := validateScanFlags(inputDir, profile, tags); err != nil {
+			return err
 		}
-		if !info.IsDir() {
-			return errs.Wrap(err, "invalid path provided for flag 'input': %s is a file, but must be a directory", inputDir)
+
+		if expose != scanner.ExposeAll && expose != scanner.ExposeRootsOnly {
+			return errs.Wrap(scanner.ErrInvalidMetadata, "invalid value provided for flag 'expose': %s (must be '%s' or '%s')", expose, scanner.ExposeAll, scanner.ExposeRootsOnly)
 		}
 
 		log.Debug("Flags are valid")
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return app.RunGenerate(inputDir, outputDir)
+		return app.RunGenerate(inputDir, outputDir, scanner.Options{Profile: profile, Tags: tags, GOOS: goos, GOARCH: goarch, Expose: expose})
 	},
 }
 
@@ -70,4 +90,9 @@ func init() {
 	rootCmd.AddCommand(generateCmd)
 	generateCmd.Flags().StringVarP(&inputDir, "input", "i", ".", "Input directory to scan")
 	generateCmd.Flags().StringVarP(&outputDir, "output", "o", "flora", "Output directory for the generated container")
+	generateCmd.Flags().StringVarP(&profile, "profile", "p", "", "Active profile; components of other profiles are ignored")
+	generateCmd.Flags().StringSliceVar(&tags, "tags", nil, "Comma-separated build tags used for scanning and added to the container's build constraint")
+	generateCmd.Flags().StringVar(&goos, "goos", "", "Target GOOS used for scanning and added to the container's build constraint")
+	generateCmd.Flags().StringVar(&goarch, "goarch", "", "Target GOARCH used for scanning and added to the container's build constraint")
+	generateCmd.Flags().StringVar(&expose, "expose", scanner.ExposeAll, "Components exposed as container fields: 'all' or 'roots-only'")
 }
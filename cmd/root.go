@@ -17,12 +17,14 @@ package cmd
 
 import (
 	"fmt"
+	"go/token"
 	"io"
 	"log/slog"
 	"os"
 	"strings"
 
 	"github.com/soner3/flora/internal/errs"
+	"github.com/soner3/flora/internal/scanner"
 	"github.com/spf13/cobra"
 )
 
@@ -54,15 +56,48 @@ initialization code at compile time.`,
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		if floraErr, ok := err.(*errs.FloraError); ok {
-			slog.Error(floraErr.Error(), "id", floraErr.ID)
-			slog.Debug("Error Stacktrace", "trace", floraErr.StackTrace)
-		} else {
-			slog.Error(err.Error())
+		logError(err)
+		os.Exit(1)
+	}
+}
+
+// logError logs every error joined into err on its own
+func logError(err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, inner := range joined.Unwrap() {
+			logError(inner)
 		}
+		return
+	}
 
-		os.Exit(1)
+	if floraErr, ok := err.(*errs.FloraError); ok {
+		slog.Error(floraErr.Error(), "id", floraErr.ID)
+		slog.Debug("Error Stacktrace", "trace", floraErr.StackTrace)
+	} else {
+		slog.Error(err.Error())
+	}
+}
+
+// validateScanFlags validates the flags that select what is scanned, shared by the commands scanning packages
+func validateScanFlags(input, profile string, tags []string) error {
+	info, err := os.Stat(input)
+	if err != nil {
+		return errs.Wrap(err, "invalid directory provided for flag 'input': %s (directory does not exist)", input)
+	}
+	if !info.IsDir() {
+		return errs.Wrap(err, "invalid path provided for flag 'input': %s is a file, but must be a directory", input)
+	}
+
+	if profile != "" && !token.IsIdentifier(profile) {
+		return errs.Wrap(scanner.ErrInvalidMetadata, "invalid value provided for flag 'profile': %s (must be a valid Go identifier)", profile)
+	}
+
+	for _, tag := range tags {
+		if !token.IsIdentifier(strings.ReplaceAll(tag, ".", "_")) {
+			return errs.Wrap(scanner.ErrInvalidMetadata, "invalid value provided for flag 'tags': %s (must be a valid build tag)", tag)
+		}
 	}
+	return nil
 }
 
 func init() {
@@ -16,7 +16,7 @@ limitations under the License.
 package main
 
 func main() {
-	// container, err := InitializeContainer()
+	// container, cleanup, err := InitializeContainer(context.Background())
 	// if err != nil {
 	// 	fmt.Printf("Failed to initialize DI container: %v\n", err)
 	// 	os.Exit(1)
@@ -15,6 +15,15 @@ limitations under the License.
 */
 package flora
 
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// ErrLazyClosed is returned by Lazy.Get after the container was cleaned up.
+var ErrLazyClosed = errors.New("flora: lazy component used after container cleanup")
+
 // Component is a marker struct that is embedded in components.
 // It allows Flora to auto-discover and wire the struct using tags.
 type Component struct{}
@@ -23,3 +32,86 @@ type Component struct{}
 // Flora will scan all methods of a Configuration struct and register them as
 // providers. Methods can be configured using magic comments (e.g., // flora:primary).
 type Configuration struct{}
+
+// Starter is implemented by components that need a start phase after the
+// whole container is built, e.g. HTTP servers or message consumers. The
+// container's Start method calls it in dependency order.
+type Starter interface {
+	Start(ctx context.Context) error
+}
+
+// Stopper is implemented by components that need to be stopped gracefully.
+// The container's Stop method calls it in reverse dependency order.
+type Stopper interface {
+	Stop(ctx context.Context) error
+}
+
+// Lazy holds a component with 'scope=lazy'. The component is built on the
+// first call to Get and at most once. Flora registers Close with the
+// container cleanup, so the component's cleanup only runs if it was built.
+type Lazy[T any] struct {
+	build   func() (T, func(), error)
+	once    sync.Once
+	mu      sync.Mutex
+	value   T
+	cleanup func()
+	err     error
+	closed  bool
+}
+
+// NewLazy returns a Lazy that builds its value with the given provider.
+// It is called by the generated container.
+func NewLazy[T any](build func() (T, func(), error)) *Lazy[T] {
+	return &Lazy[T]{build: build}
+}
+
+// Get builds the component on first use and returns it. A failed build is
+// not retried, every call returns the same error. After Close it returns
+// ErrLazyClosed.
+func (l *Lazy[T]) Get() (T, error) {
+	l.once.Do(func() {
+		l.mu.Lock()
+		closed := l.closed
+		l.mu.Unlock()
+		if closed {
+			return
+		}
+
+		value, cleanup, err := l.build()
+
+		// Close may have run while building, the component is then cleaned up right away
+		l.mu.Lock()
+		closed = l.closed
+		if !closed {
+			l.value, l.cleanup, l.err = value, cleanup, err
+		}
+		l.mu.Unlock()
+		if closed && cleanup != nil {
+			cleanup()
+		}
+	})
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		var zero T
+		return zero, ErrLazyClosed
+	}
+	return l.value, l.err
+}
+
+// Close runs the component's cleanup if it was built. Afterwards Get
+// returns ErrLazyClosed.
+func (l *Lazy[T]) Close() {
+	l.mu.Lock()
+	l.closed = true
+	cleanup := l.cleanup
+	l.cleanup = nil
+	var zero T
+	l.value = zero
+	l.mu.Unlock()
+
+	if cleanup != nil {
+		cleanup()
+	}
+}
@@ -0,0 +1,93 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package flora
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestLazy(t *testing.T) {
+	t.Run("TestBuildsOnceOnFirstUse", func(t *testing.T) {
+		builds, cleanups := 0, 0
+		lazy := NewLazy(func() (int, func(), error) {
+			builds++
+			return 42, func() { cleanups++ }, nil
+		})
+
+		if builds != 0 {
+			t.Fatalf("expected no build before Get, got %d", builds)
+		}
+
+		var wg sync.WaitGroup
+		for range 10 {
+			wg.Go(func() {
+				if v, err := lazy.Get(); v != 42 || err != nil {
+					t.Errorf("expected 42, got %d, %v", v, err)
+				}
+			})
+		}
+		wg.Wait()
+
+		lazy.Close()
+		lazy.Close()
+
+		if builds != 1 || cleanups != 1 {
+			t.Errorf("expected 1 build and 1 cleanup, got %d and %d", builds, cleanups)
+		}
+	})
+
+	t.Run("TestCloseWithoutBuild", func(t *testing.T) {
+		lazy := NewLazy(func() (int, func(), error) {
+			t.Error("expected no build")
+			return 0, nil, nil
+		})
+
+		lazy.Close()
+
+		if _, err := lazy.Get(); !errors.Is(err, ErrLazyClosed) {
+			t.Errorf("expected ErrLazyClosed, got %v", err)
+		}
+	})
+
+	t.Run("TestGetAfterCloseOfBuiltComponent", func(t *testing.T) {
+		lazy := NewLazy(func() (int, func(), error) {
+			return 42, func() {}, nil
+		})
+
+		if v, err := lazy.Get(); v != 42 || err != nil {
+			t.Fatalf("expected 42, got %d, %v", v, err)
+		}
+		lazy.Close()
+
+		if v, err := lazy.Get(); v != 0 || !errors.Is(err, ErrLazyClosed) {
+			t.Errorf("expected ErrLazyClosed, got %d, %v", v, err)
+		}
+	})
+
+	t.Run("TestBuildError", func(t *testing.T) {
+		expErr := errors.New("connect failed")
+		lazy := NewLazy(func() (int, func(), error) {
+			return 0, nil, expErr
+		})
+
+		if _, err := lazy.Get(); !errors.Is(err, expErr) {
+			t.Errorf("expected %v, got %v", expErr, err)
+		}
+		lazy.Close()
+	})
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"cmp"
+	"fmt"
+	"go/token"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/soner3/flora/internal/engine"
+	"github.com/soner3/flora/internal/scanner"
+)
+
+// finding is one unused part of the graph
+type finding struct {
+	pos  token.Position
+	desc string
+}
+
+// RunAnalyzeUnused writes every component, configuration method, provider and
+// slice or map binding that no declared root consumes to w
+func RunAnalyzeUnused(inputDir string, opts scanner.Options, w io.Writer) error {
+	log := slog.With("pkg", "app")
+
+	log.Info("Analyzing unused components...", "dir", inputDir, "profile", opts.Profile, "tags", opts.Tags)
+
+	pkgs, err := scanner.ScanPackages(inputDir, opts)
+	if err != nil {
+		return err
+	}
+
+	genCtx, err := scanner.ParsePackages(pkgs, opts)
+	if err != nil {
+		return err
+	}
+
+	report, err := scanner.FindUnused(genCtx)
+	if err != nil {
+		return err
+	}
+
+	var findings []finding
+	for _, comp := range report.Components {
+		findings = append(findings, finding{pos: comp.Position, desc: describeComponent(comp)})
+	}
+	for _, sb := range report.SliceBindings {
+		findings = append(findings, finding{pos: sb.Position, desc: "slice binding []" + describeInterface(sb.Interface)})
+	}
+	for _, mb := range report.MapBindings {
+		findings = append(findings, finding{pos: mb.Position, desc: "map binding map[string]" + describeInterface(mb.Interface)})
+	}
+
+	slices.SortFunc(findings, func(a, b finding) int {
+		return cmp.Or(cmp.Compare(a.pos.Filename, b.pos.Filename), cmp.Compare(a.pos.Line, b.pos.Line), cmp.Compare(a.pos.Column, b.pos.Column))
+	})
+
+	wd, _ := os.Getwd()
+	for _, f := range findings {
+		if rel, err := filepath.Rel(wd, f.pos.Filename); err == nil && wd != "" {
+			f.pos.Filename = rel
+		}
+		if _, err := fmt.Fprintf(w, "%s: %s is never used\n", f.pos, f.desc); err != nil {
+			return err
+		}
+	}
+
+	log.Info("Analysis complete", "components", len(genCtx.Components), "unused", len(findings))
+	return nil
+}
+
+// describeComponent names a component the way it is declared
+func describeComponent(comp *engine.ComponentMetadata) string {
+	var desc string
+	switch {
+	case comp.ConfigMethodName != "":
+		desc = fmt.Sprintf("configuration method %s.%s.%s", comp.ConfigPackageName, comp.ConfigStructName, comp.ConfigMethodName)
+	case comp.ProviderPackagePath != "":
+		desc = fmt.Sprintf("provider %s.%s", comp.ProviderPackageName, comp.ConstructorName)
+	default:
+		desc = fmt.Sprintf("component %s.%s%s", comp.PackageName, comp.StructName, typeArgs(comp.TypeArgs))
+	}
+	// The links of a decorator chain carry internal qualifiers, only the decorators are described by them
+	if decorated, link, ok := scanner.DecoratorLink(comp.Qualifier); ok {
+		if link > 0 {
+			desc += fmt.Sprintf(" as decorated %s (link %d)", decorated[strings.LastIndex(decorated, ".")+1:], link)
+		}
+	} else if comp.Qualifier != "" {
+		desc += fmt.Sprintf(" named '%s'", comp.Qualifier)
+	}
+	return desc
+}
+
+// describeInterface renders an interface with its package and type arguments
+func describeInterface(iface engine.InterfaceMetadata) string {
+	return iface.PackageName + "." + iface.InterfaceName + typeArgs(iface.TypeArgs)
+}
+
+// typeArgs renders type arguments as they are written in Go
+func typeArgs(args []engine.TypeArgMetadata) string {
+	if len(args) == 0 {
+		return ""
+	}
+	var types []string
+	for _, arg := range args {
+		types = append(types, arg.Type)
+	}
+	return "[" + strings.Join(types, ", ") + "]"
+}
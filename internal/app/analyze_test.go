@@ -0,0 +1,80 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package app
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/soner3/flora/internal/scanner"
+)
+
+func TestRunAnalyzeUnused(t *testing.T) {
+	testcases := []struct {
+		name    string
+		dir     string
+		expOut  []string
+		wantErr bool
+	}{
+		{
+			name:    "TestScanError",
+			dir:     "./testdata/scan_err",
+			wantErr: true,
+		},
+		{
+			name:    "TestNoRoots",
+			dir:     "./testdata/happy",
+			wantErr: true,
+		},
+		{
+			name: "TestUnused",
+			dir:  "./testdata/unused",
+			expOut: []string{
+				"main.go:32:21: configuration method main.AppConfig.ProvideCache is never used",
+				"main.go:50:6: map binding map[string]main.Handler is never used",
+				"main.go:69:6: component main.LegacyRouter is never used",
+				"main.go:86:6: provider main.NewClock is never used",
+				"main.go:88:6: component main.Mailer is never used",
+				"main.go:95:6: provider main.WithRetries as decorated Mailer (link 1) is never used",
+				"main.go:98:6: provider main.WithMetrics is never used",
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := RunAnalyzeUnused(tc.dir, scanner.Options{}, &out)
+
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, but got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("did not expect an error, but got: %v", err)
+			}
+
+			for _, line := range tc.expOut {
+				if !strings.Contains(out.String(), line) {
+					t.Errorf("expected output to contain %q, got:\n%s", line, out.String())
+				}
+			}
+		})
+	}
+}
@@ -22,18 +22,18 @@ import (
 	"github.com/soner3/flora/internal/scanner"
 )
 
-func RunGenerate(inputDir, outputDir string) error {
+func RunGenerate(inputDir, outputDir string, opts scanner.Options) error {
 	log := slog.With("pkg", "app")
 
-	log.Info("Starting flora generation...", "dir", inputDir, "out", outputDir)
+	log.Info("Starting flora generation...", "dir", inputDir, "out", outputDir, "profile", opts.Profile, "tags", opts.Tags)
 
 	log.Debug("Scanning packages for flora components...")
-	pkgs, err := scanner.ScanPackages(inputDir)
+	pkgs, err := scanner.ScanPackages(inputDir, opts)
 	if err != nil {
 		return err
 	}
 
-	genCtx, err := scanner.ParsePackages(pkgs)
+	genCtx, err := scanner.ParsePackages(pkgs, opts)
 	if err != nil {
 		return err
 	}
@@ -18,6 +18,8 @@ package app
 import (
 	"os"
 	"testing"
+
+	"github.com/soner3/flora/internal/scanner"
 )
 
 func TestRunGenerate(t *testing.T) {
@@ -25,6 +27,7 @@ func TestRunGenerate(t *testing.T) {
 		name    string
 		dir     string
 		outDir  string
+		opts    scanner.Options
 		wantErr bool
 	}{
 		{
@@ -57,6 +60,13 @@ func TestRunGenerate(t *testing.T) {
 			outDir:  "",
 			wantErr: false,
 		},
+		{
+			name:    "TestSuccessWithProfile",
+			dir:     "./testdata/happy",
+			outDir:  "",
+			opts:    scanner.Options{Profile: "dev"},
+			wantErr: false,
+		},
 	}
 
 	for _, tc := range testcases {
@@ -72,7 +82,7 @@ func TestRunGenerate(t *testing.T) {
 				outDir = tmpDir
 			}
 
-			err := RunGenerate(tc.dir, outDir)
+			err := RunGenerate(tc.dir, outDir, tc.opts)
 
 			if tc.wantErr {
 				if err == nil {
@@ -15,45 +15,134 @@ limitations under the License.
 */
 package engine
 
+import "go/token"
+
 type InterfaceMetadata struct {
 	PackageName   string
 	PackagePath   string
 	InterfaceName string
+	TypeArgs      []TypeArgMetadata
+	// Explicit is set for interfaces bound with 'bind=', they are exposed on the container
+	Explicit bool
 }
 
-type ParamMetadata struct {
-	Name    string
+// Key identifies an interface with its type arguments
+func (i InterfaceMetadata) Key() string {
+	key := i.PackagePath + "." + i.InterfaceName
+	for _, arg := range i.TypeArgs {
+		key += "," + arg.Type
+	}
+	return key
+}
+
+type TypeArgMetadata struct {
 	Type    string
 	Imports []string
 }
 
+type ParamMetadata struct {
+	Name      string
+	Type      string
+	Imports   []string
+	Qualifier string
+	// Source tells how the parameter is satisfied, see the Source constants
+	Source string
+	// Providers are the components the parameter is resolved to
+	Providers []*ComponentMetadata
+	// Interface is set for slices, maps, interface factories and bound interfaces
+	Interface *InterfaceMetadata
+}
+
+const (
+	// SourceComponent is the value, factory or lazy holder of Providers[0]
+	SourceComponent = "component"
+	// SourceFactory is the 'func() Interface' factory of a prototype
+	SourceFactory = "factory"
+	// SourceSlice and SourceMap collect all Providers
+	SourceSlice = "slice"
+	SourceMap   = "map"
+	// SourceContext is the context.Context passed to the container
+	SourceContext = "context"
+)
+
 type ComponentMetadata struct {
-	PackageName       string
-	PackagePath       string
-	StructName        string
-	ConstructorName   string
-	IsPrimary         bool
-	Scope             string
-	IsPointer         bool
-	HasCleanup        bool
-	HasError          bool
-	Order             int
-	ConfigStructName  string
-	ConfigMethodName  string
-	ConfigPackageName string
-	ConfigPackagePath string
-	Implements        []InterfaceMetadata
-	Params            []ParamMetadata
+	PackageName         string
+	PackagePath         string
+	StructName          string
+	ConstructorName     string
+	IsPrimary           bool
+	Scope               string
+	IsPointer           bool
+	HasCleanup          bool
+	HasError            bool
+	Order               int
+	ConfigStructName    string
+	ConfigMethodName    string
+	ConfigPackageName   string
+	ConfigPackagePath   string
+	ConfigInstance      bool
+	ProviderPackageName string
+	ProviderPackagePath string
+	Qualifier           string
+	ParamQualifiers     map[string]string
+	Profiles            []string
+	Conditional         string
+	Key                 string
+	Binds               []string
+	NoBinds             []string
+	Decorates           string
+	Modules             []string
+	// Position is where the struct, provider func or configuration method is declared
+	Position token.Position
+	Expose   bool
+	Internal bool
+	// Hidden components are no exported container fields, they are only
+	// built when something depends on them
+	Hidden       bool
+	Autowire     bool
+	InjectFields []string
+	IsStarter    bool
+	IsStopper    bool
+	TypeArgs     []TypeArgMetadata
+	Implements   []InterfaceMetadata
+	Params       []ParamMetadata
+}
+
+// ModuleMetadata holds the roots of a module and everything they depend on
+type ModuleMetadata struct {
+	Name       string
+	Components []*ComponentMetadata
 }
 
 type SliceBindingMetadata struct {
 	Interface       InterfaceMetadata
 	Implementations []*ComponentMetadata
+	// Position is where the interface is declared
+	Position token.Position
+}
+
+type MapBindingMetadata struct {
+	Interface       InterfaceMetadata
+	Implementations []*ComponentMetadata
+	// Position is where the interface is declared
+	Position token.Position
 }
 
 type GeneratorContext struct {
 	Components    []*ComponentMetadata
 	SliceBindings []*SliceBindingMetadata
+	MapBindings   []*MapBindingMetadata
+	// Lifecycle holds the singletons implementing flora.Starter or
+	// flora.Stopper in dependency order
+	Lifecycle []*ComponentMetadata
+	// Modules holds one entry per 'module=' with the components its container needs
+	Modules []*ModuleMetadata
+	// Expose is the mode that selected the hidden components
+	Expose  string
+	Profile string
+	Tags    []string
+	GOOS    string
+	GOARCH  string
 }
 
 type Generator interface {
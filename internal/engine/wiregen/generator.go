@@ -20,17 +20,30 @@ import (
 	"cmp"
 	"errors"
 	"fmt"
+	"go/ast"
 	"go/build"
+	"go/build/constraint"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
 	"log/slog"
+	"maps"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"slices"
+	"strconv"
 	"strings"
 	"text/template"
+	"unicode"
 
 	"github.com/soner3/flora/internal/engine"
 	"github.com/soner3/flora/internal/errs"
+	"github.com/soner3/flora/internal/scanner"
+	"golang.org/x/tools/go/ast/astutil"
+	"golang.org/x/tools/imports"
 )
 
 var (
@@ -44,8 +57,12 @@ var (
 	ErrEnsureWireDependency = errors.New("failed to ensure google/wire dependency")
 	ErrWireExecution        = errors.New("flora engine failed to resolve dependency graph")
 	ErrRenameGeneratedFile  = errors.New("failed to rename generated container file")
+	ErrBuildConstraint      = errors.New("failed to add build constraint to generated container file")
+	ErrGenericDecls         = errors.New("failed to place generic declarations")
 )
 
+const floraPkgPath = "github.com/soner3/flora"
+
 type WireGenerator struct{}
 
 func NewWireGenerator() *WireGenerator {
@@ -63,6 +80,285 @@ func isBuiltInType(name string) bool {
 	return false
 }
 
+// qualifiedTypeName returns the name of the distinct type that carries a
+// named component through the wire graph
+func qualifiedTypeName(qualifier string) string {
+	return "Qualified_" + qualifierIdent(qualifier)
+}
+
+// qualifierIdent turns a qualifier into a Go identifier. Qualifiers given with
+// 'name=' already are one. The internal ones of decorator chains contain a
+// package path, they keep its last element and get a hash of the full path.
+func qualifierIdent(qualifier string) string {
+	if token.IsIdentifier(qualifier) {
+		return qualifier
+	}
+	kind, ref, _ := strings.Cut(qualifier, ":")
+	ref = ref[strings.LastIndex(ref, "/")+1:]
+	ident := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return r
+		}
+		return '_'
+	}, kind+"_"+ref)
+	return fmt.Sprintf("%s_%08x", ident, uint32(errs.GenerateHash([]byte(qualifier))))
+}
+
+// exportedName upper-cases the first letter so a qualifier can be used as a container field
+func exportedName(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+
+// unexportedName lower-cases the first letter so a hidden component keeps its field private
+func unexportedName(name string) string {
+	r := []rune(name)
+	r[0] = unicode.ToLower(r[0])
+	if token.IsKeyword(string(r)) {
+		return string(r) + "_"
+	}
+	return string(r)
+}
+
+// containerFieldName returns the name of the field that holds a component
+// in the FloraContainer (or the FloraScope for request-scoped components)
+func containerFieldName(comp *engine.ComponentMetadata) string {
+	name := comp.StructName + typeArgSuffix(comp.TypeArgs)
+	if comp.Qualifier != "" {
+		name = exportedName(qualifierIdent(comp.Qualifier))
+	}
+	if comp.Scope == "prototype" {
+		name += "Factory"
+	}
+	if comp.Hidden {
+		name = unexportedName(name)
+	}
+	return name
+}
+
+// collectionFieldName returns the field of the slice, map or interface
+// factory a parameter is injected with
+func collectionFieldName(p engine.ParamMetadata) string {
+	switch p.Source {
+	case engine.SourceSlice:
+		return "SliceOf" + p.Interface.InterfaceName + typeArgSuffix(p.Interface.TypeArgs)
+	case engine.SourceMap:
+		return "MapOf" + p.Interface.InterfaceName + typeArgSuffix(p.Interface.TypeArgs)
+	case engine.SourceFactory:
+		return p.Interface.InterfaceName + typeArgSuffix(p.Interface.TypeArgs) + "Factory"
+	}
+	return ""
+}
+
+// parentFieldExpr renders how a request-scoped component reads a parameter
+// from the parent container
+func parentFieldExpr(p engine.ParamMetadata) string {
+	field := collectionFieldName(p)
+	if field == "" {
+		field = containerFieldName(p.Providers[0])
+	}
+	return "parent." + field
+}
+
+// factoryType renders the closure type of a prototype factory
+func factoryType(retType string, hasCleanup, hasError bool) string {
+	results := retType
+	if hasCleanup {
+		results += ", func()"
+	}
+	if hasError {
+		results += ", error"
+	}
+	return "func() (" + results + ")"
+}
+
+var pkgQualifierPattern = regexp.MustCompile(`(^|[^\w.])(\w+)\.`)
+
+// localizeType drops the qualifier of the generated package from a rendered
+// type, e.g. "*app.Repository[app.User]" becomes "*Repository[User]" in package app
+func localizeType(typeStr, pkgName string) string {
+	return pkgQualifierPattern.ReplaceAllStringFunc(typeStr, func(m string) string {
+		sub := pkgQualifierPattern.FindStringSubmatch(m)
+		if sub[2] == pkgName {
+			return sub[1]
+		}
+		return m
+	})
+}
+
+// typeArgList renders the type arguments of an instantiated generic type,
+// e.g. "[User, int]", and registers their imports
+func typeArgList(typeArgs []engine.TypeArgMetadata, pkgName string, importSet map[string]bool) string {
+	if len(typeArgs) == 0 {
+		return ""
+	}
+	args := make([]string, 0, len(typeArgs))
+	for _, arg := range typeArgs {
+		for _, imp := range arg.Imports {
+			importSet[imp] = true
+		}
+		args = append(args, localizeType(arg.Type, pkgName))
+	}
+	return "[" + strings.Join(args, ", ") + "]"
+}
+
+// typeArgSuffix turns type arguments into a suffix that keeps the generated
+// identifiers of different instantiations apart, e.g. "_User" or "_PtrUser_int"
+func typeArgSuffix(typeArgs []engine.TypeArgMetadata) string {
+	var b strings.Builder
+	for _, arg := range typeArgs {
+		b.WriteByte('_')
+		for _, r := range pkgQualifierPattern.ReplaceAllString(arg.Type, "$1") {
+			switch {
+			case r == '*':
+				b.WriteString("Ptr")
+			case unicode.IsLetter(r) || unicode.IsDigit(r):
+				b.WriteRune(r)
+			}
+		}
+	}
+	return b.String()
+}
+
+// narrowContainer keeps the parts of the graph that build the fields of a
+// container: the components they transitively depend on and the bindings,
+// factories and collections those are injected with. The collections named
+// in exposed are fields in any case, with exposeNeeded every collection the
+// components need becomes one.
+func narrowContainer(fields []*engine.ComponentMetadata, exposed map[string]bool, exposeNeeded bool, data templateData) moduleData {
+	isField := make(map[*engine.ComponentMetadata]bool)
+	for _, comp := range fields {
+		isField[comp] = true
+	}
+
+	queue := slices.Clone(fields)
+	for _, sb := range slices.Concat(data.SliceBindings, data.MapBindings) {
+		if exposed[sb.field] {
+			queue = append(queue, sb.impls...)
+		}
+	}
+	for _, p := range data.Prototypes {
+		if p.iface != "" && exposed[p.FieldName] {
+			queue = append(queue, p.owner)
+		}
+	}
+
+	// direct components are injected as themselves, not as an interface factory
+	included := make(map[*engine.ComponentMetadata]bool)
+	direct := maps.Clone(isField)
+	needed := make(map[string]bool)
+	for len(queue) > 0 {
+		comp := queue[0]
+		queue = queue[1:]
+		if included[comp] {
+			continue
+		}
+		included[comp] = true
+		for _, param := range comp.Params {
+			queue = append(queue, param.Providers...)
+			if param.Source != engine.SourceFactory {
+				for _, provider := range param.Providers {
+					direct[provider] = true
+				}
+			}
+			if param.Interface == nil {
+				continue
+			}
+			if field := collectionFieldName(param); field != "" {
+				needed[field] = true
+			} else {
+				needed[param.Interface.Key()] = true
+			}
+		}
+	}
+
+	isExposed := func(field string) bool {
+		return exposed[field] || (exposeNeeded && needed[field])
+	}
+
+	var md moduleData
+	for _, p := range data.Providers {
+		if included[p.owner] {
+			if !isField[p.owner] {
+				p.FieldName = ""
+			}
+			md.Providers = append(md.Providers, p)
+		}
+	}
+	for _, p := range data.Prototypes {
+		if !included[p.owner] {
+			continue
+		}
+		if p.iface != "" {
+			if !exposed[p.FieldName] && !needed[p.FieldName] {
+				continue
+			}
+			if !isExposed(p.FieldName) {
+				p.FieldName = ""
+			}
+		} else if !direct[p.owner] {
+			continue
+		} else if !isField[p.owner] {
+			p.FieldName = ""
+		}
+		md.Prototypes = append(md.Prototypes, p)
+	}
+	for _, l := range data.Lazies {
+		if included[l.owner] {
+			if !isField[l.owner] {
+				l.FieldName = ""
+			}
+			md.Lazies = append(md.Lazies, l)
+		}
+	}
+	for _, b := range data.Bindings {
+		if !included[b.owner] {
+			continue
+		}
+		if b.owner.Hidden {
+			b.FieldName = ""
+		}
+		if b.FieldName != "" || needed[b.iface] {
+			md.Bindings = append(md.Bindings, b)
+		}
+	}
+	for _, sb := range data.SliceBindings {
+		if exposed[sb.field] || needed[sb.field] {
+			sb.Exposed = isExposed(sb.field)
+			md.SliceBindings = append(md.SliceBindings, sb)
+		}
+	}
+	for _, mb := range data.MapBindings {
+		if exposed[mb.field] || needed[mb.field] {
+			mb.Exposed = isExposed(mb.field)
+			md.MapBindings = append(md.MapBindings, mb)
+		}
+	}
+	for _, l := range data.Lifecycle {
+		if included[l.owner] {
+			md.Lifecycle = append(md.Lifecycle, l)
+		}
+	}
+
+	for _, p := range md.Providers {
+		if p.FieldName != "" && p.QualifiedType != "" {
+			md.Copies = append(md.Copies, copyData{FieldName: p.FieldName, ProvidedType: p.ProvidedType})
+		}
+	}
+	for _, p := range md.Prototypes {
+		if p.FieldName != "" && p.QualifiedType != "" {
+			md.Copies = append(md.Copies, copyData{FieldName: p.FieldName, ProvidedType: p.ProvidedType})
+		}
+	}
+	for _, l := range md.Lazies {
+		if l.FieldName != "" && l.QualifiedType != "" {
+			md.Copies = append(md.Copies, copyData{FieldName: l.FieldName, ProvidedType: l.ProvidedType})
+		}
+	}
+	return md
+}
+
 var wireTemplate = `//go:build wireinject
 // +build wireinject
 
@@ -75,85 +371,345 @@ import (
     {{end}}
 )
 
+{{range .Qualifiers}}
+type {{.TypeName}} {{.Underlying}}
+{{end}}
+
+{{range .Injected}}
+func {{.ConstructorName}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Name}} {{$param.Type}}{{end}}) *{{.StructType}} {
+    return &{{.StructType}}{
+        {{range $index, $field := .Fields}}{{$field}}: p{{$index}},
+        {{end}}
+    }
+}
+{{end}}
+
 {{range .ConfigWrappers}}
 {{if .IsPrototype}}
-func {{.WrapperName}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Name}} {{$param.Type}}{{end}}) func() ({{.ReturnType}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}}) {
-    return func() ({{.ReturnType}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}}) {
-        cfg := {{.ConfigPackagePrefix}}{{.ConfigStructName}}{}
-        return cfg.{{.ConfigMethodName}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Name}}{{end}})
-    }
+func {{.WrapperName}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Name}} {{$param.Type}}{{end}}) {{if .QualifiedType}}{{.QualifiedType}}{{else}}func() ({{.ReturnType}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}}){{end}} {
+    return {{if .QualifiedType}}{{.QualifiedType}}({{end}}func() ({{.ReturnType}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}}) {
+        cfg := {{.ConfigReceiver}}
+        return cfg.{{.ConfigMethodName}}({{range $index, $param := .Args}}{{if $index}}, {{end}}{{$param.Arg}}{{end}})
+    }{{if .QualifiedType}}){{end}}
 }
 {{else}}
-func {{.WrapperName}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Name}} {{$param.Type}}{{end}}) ({{.ReturnType}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}}) {
-    cfg := {{.ConfigPackagePrefix}}{{.ConfigStructName}}{}
-    return cfg.{{.ConfigMethodName}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Name}}{{end}})
+func {{.WrapperName}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Name}} {{$param.Type}}{{end}}) ({{if .QualifiedType}}{{.QualifiedType}}{{else}}{{.ReturnType}}{{end}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}}) {
+    cfg := {{.ConfigReceiver}}
+    {{- if .QualifiedType}}
+    v{{if .HasCleanup}}, cleanup{{end}}{{if .HasError}}, err{{end}} := cfg.{{.ConfigMethodName}}({{range $index, $param := .Args}}{{if $index}}, {{end}}{{$param.Arg}}{{end}})
+    return {{.QualifiedType}}(v){{if .HasCleanup}}, cleanup{{end}}{{if .HasError}}, err{{end}}
+    {{- else}}
+    return cfg.{{.ConfigMethodName}}({{range $index, $param := .Args}}{{if $index}}, {{end}}{{$param.Arg}}{{end}})
+    {{- end}}
+}
+{{end}}
+{{end}}
+
+{{range .Providers}}
+{{if .IsWrapper}}
+func {{.ConstructorName}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Name}} {{$param.Type}}{{end}}) ({{.FieldType}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}}) {
+    {{- if .QualifiedType}}
+    v{{if .HasCleanup}}, cleanup{{end}}{{if .HasError}}, err{{end}} := {{.ConstructorCall}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Arg}}{{end}})
+    return {{.QualifiedType}}(v){{if .HasCleanup}}, cleanup{{end}}{{if .HasError}}, err{{end}}
+    {{- else}}
+    return {{.ConstructorCall}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Arg}}{{end}})
+    {{- end}}
 }
 {{end}}
 {{end}}
 
 {{range .Prototypes}}
 {{if not .IsConfig}}
-func {{.WrapperName}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Name}} {{$param.Type}}{{end}}) func() ({{.ReturnType}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}}) {
-    return func() ({{.ReturnType}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}}) {
-        return {{.ConstructorCall}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Name}}{{end}})
+func {{.WrapperName}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Name}} {{$param.Type}}{{end}}) {{if .QualifiedType}}{{.QualifiedType}}{{else}}func() ({{.ReturnType}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}}){{end}} {
+    return {{if .QualifiedType}}{{.QualifiedType}}({{end}}func() ({{.ReturnType}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}}) {
+        return {{.ConstructorCall}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Arg}}{{end}})
+    }{{if .QualifiedType}}){{end}}
+}
+{{end}}
+{{end}}
+
+{{range .MapBindings}}
+func ProvideMapOf{{.InterfaceName}}{{.NameSuffix}}({{range .Implementations}}{{.ParamName}} {{.ParamType}}, {{end}}) map[string]{{.InterfacePrefix}}{{.InterfaceName}}{{.TypeArgs}} {
+    return map[string]{{.InterfacePrefix}}{{.InterfaceName}}{{.TypeArgs}}{
+        {{range .Implementations}}{{.Key}}: {{.Arg}},
+        {{end}}
     }
 }
 {{end}}
+
+{{range .Lazies}}
+func {{.WrapperName}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Name}} {{$param.Type}}{{end}}) ({{if .QualifiedType}}{{.QualifiedType}}{{else}}*flora.Lazy[{{.ReturnType}}]{{end}}, func()) {
+    lazy := flora.NewLazy(func() ({{.ReturnType}}, func(), error) {
+        {{- if .IsConfig}}
+        cfg := {{.ConfigReceiver}}
+        {{- end}}
+        {{- if and .HasCleanup .HasError}}
+        return {{.ConstructorCall}}({{range $index, $param := .Args}}{{if $index}}, {{end}}{{$param.Arg}}{{end}})
+        {{- else if .HasCleanup}}
+        v, cleanup := {{.ConstructorCall}}({{range $index, $param := .Args}}{{if $index}}, {{end}}{{$param.Arg}}{{end}})
+        return v, cleanup, nil
+        {{- else if .HasError}}
+        v, err := {{.ConstructorCall}}({{range $index, $param := .Args}}{{if $index}}, {{end}}{{$param.Arg}}{{end}})
+        return v, nil, err
+        {{- else}}
+        return {{.ConstructorCall}}({{range $index, $param := .Args}}{{if $index}}, {{end}}{{$param.Arg}}{{end}}), nil, nil
+        {{- end}}
+    })
+    return {{if .QualifiedType}}{{.QualifiedType}}(lazy){{else}}lazy{{end}}, lazy.Close
+}
 {{end}}
 
 {{range .SliceBindings}}
-func ProvideSliceOf{{.InterfaceName}}({{range .Implementations}}{{.ParamName}} {{.TypePrefix}}{{.StructName}}, {{end}}) []{{.InterfacePrefix}}{{.InterfaceName}} {
-    return []{{.InterfacePrefix}}{{.InterfaceName}}{
-        {{range .Implementations}}{{.ParamName}},{{end}}
+func ProvideSliceOf{{.InterfaceName}}{{.NameSuffix}}({{range .Implementations}}{{.ParamName}} {{.ParamType}}, {{end}}) []{{.InterfacePrefix}}{{.InterfaceName}}{{.TypeArgs}} {
+    return []{{.InterfacePrefix}}{{.InterfaceName}}{{.TypeArgs}}{
+        {{range .Implementations}}{{.Arg}},{{end}}
     }
 }
 {{end}}
 
 type FloraContainer struct {
-    {{range .Providers}}
-    {{.StructName}} {{if .IsPointer}}*{{end}}{{.TypePrefix}}{{.StructName}}
-    {{end}}
-    
-    {{range .Prototypes}}
-    {{.FieldName}} func() ({{.ReturnType}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}})
-    {{end}}
+    {{template "containerFields" .}}
+}
+
+{{template "lifecycle" .}}
+
+{{if .Lifecycle}}
+func floraStopAll(ctx context.Context, stops []func(context.Context) error) error {
+    var errs []error
+    for i := len(stops) - 1; i >= 0; i-- {
+        if err := stops[i](ctx); err != nil {
+            errs = append(errs, err)
+        }
+    }
+    return errors.Join(errs...)
+}
+{{end}}
+
+{{if .Scoped}}
+{{range .Scoped}}
+func {{.WrapperName}}({{range $index, $param := .Params}}{{if $index}}, {{end}}{{$param.Name}} {{$param.Type}}{{end}}) ({{.FieldType}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}}) {
+    {{- if .IsConfig}}
+    cfg := {{.ConfigReceiver}}
+    {{- end}}
+    {{- if .QualifiedType}}
+    v{{if .HasCleanup}}, cleanup{{end}}{{if .HasError}}, err{{end}} := {{.ConstructorCall}}({{range $index, $arg := .Args}}{{if $index}}, {{end}}{{$arg}}{{end}})
+    return {{.QualifiedType}}(v){{if .HasCleanup}}, cleanup{{end}}{{if .HasError}}, err{{end}}
+    {{- else}}
+    return {{.ConstructorCall}}({{range $index, $arg := .Args}}{{if $index}}, {{end}}{{$arg}}{{end}})
+    {{- end}}
+}
+{{end}}
 
-    {{range .SliceBindings}}
-    SliceOf{{.InterfaceName}} []{{.InterfacePrefix}}{{.InterfaceName}}
+type FloraScope struct {
+    {{range .Scoped}}
+    {{if .QualifiedType}}{{template "qualifiedField" .}}{{else}}{{.FieldName}} {{.FieldType}}{{end}}
     {{end}}
+
+    {{range .ScopedBindings}}{{if .FieldName}}
+    {{.FieldName}} {{.InterfacePrefix}}{{.InterfaceName}}
+    {{end}}{{end}}
+}
+
+// NewScope returns a child container for one request or unit of work. It
+// builds every request-scoped component once and reuses the singletons of
+// the container. The returned cleanup closes the request-scoped components.
+func (c *FloraContainer) NewScope(ctx context.Context) (*FloraScope, func(), error) {
+    {{- if .ScopedCopies}}
+    s, cleanup, err := initializeScope(ctx, c)
+    if err != nil {
+        return nil, nil, err
+    }
+    {{- range .ScopedCopies}}
+    s.{{.FieldName}} = ({{.ProvidedType}})(s.qualified_{{.FieldName}})
+    {{- end}}
+    return s, cleanup, nil
+    {{- else}}
+    return initializeScope(ctx, c)
+    {{- end}}
+}
+
+func initializeScope(ctx context.Context, parent *FloraContainer) (*FloraScope, func(), error) {
+    wire.Build(
+        {{range .Scoped}}
+        {{.WrapperName}},
+        {{end}}
+        {{range .ScopedBindings}}
+        wire.Bind(new({{.InterfacePrefix}}{{.InterfaceName}}), new({{if .IsPointer}}*{{end}}{{.ComponentPrefix}}{{.StructName}})),
+        {{end}}
+        wire.Struct(new(FloraScope), "*"),
+    )
+    return nil, nil, nil
+}
+{{end}}
+
+{{if .Copies}}
+func InitializeContainer(ctx context.Context) (*FloraContainer, func(), error) {
+    c, cleanup, err := initializeFloraContainer(ctx)
+    if err != nil {
+        return nil, nil, err
+    }
+    {{- template "copies" .}}
+    return c, cleanup, nil
+}
+{{end}}
+
+func {{if .Copies}}initializeFloraContainer{{else}}InitializeContainer{{end}}(ctx context.Context) (*FloraContainer, func(), error) {
+    wire.Build(
+        {{template "containerBuild" .}}
+        wire.Struct(new(FloraContainer), "*"),
+    )
+    return nil, nil, nil
+}
+
+{{range .Modules}}
+// {{.Container}} holds the roots of module '{{.Name}}' and everything they depend on.
+type {{.Container}} struct {
+    {{template "containerFields" .}}
 }
 
-func InitializeContainer() (*FloraContainer, func(), error) {
+{{template "lifecycle" .}}
+
+{{if .Copies}}
+func Initialize{{.Container}}(ctx context.Context) (*{{.Container}}, func(), error) {
+    c, cleanup, err := initialize{{.Container}}(ctx)
+    if err != nil {
+        return nil, nil, err
+    }
+    {{- template "copies" .}}
+    return c, cleanup, nil
+}
+{{end}}
+
+func {{if .Copies}}initialize{{else}}Initialize{{end}}{{.Container}}(ctx context.Context) (*{{.Container}}, func(), error) {
     wire.Build(
+        {{template "containerBuild" .}}
+        wire.Struct(new({{.Container}}), "*"),
+    )
+    return nil, nil, nil
+}
+{{end}}
+
+{{define "containerFields"}}
+    {{range .Providers}}{{if .FieldName}}
+    {{if .QualifiedType}}{{template "qualifiedField" .}}{{else}}{{.FieldName}} {{.FieldType}}{{end}}
+    {{end}}{{end}}
+    
+    {{range .Prototypes}}{{if .FieldName}}
+    {{if .QualifiedType}}{{template "qualifiedField" .}}{{else}}{{.FieldName}} func() ({{.ReturnType}}{{if .HasCleanup}}, func(){{end}}{{if .HasError}}, error{{end}}){{end}}
+    {{end}}{{end}}
+
+    {{range .Lazies}}{{if .FieldName}}
+    {{if .QualifiedType}}{{template "qualifiedField" .}}{{else}}{{.FieldName}} *flora.Lazy[{{.ReturnType}}]{{end}}
+    {{end}}{{end}}
+
+    {{range .SliceBindings}}{{if .Exposed}}
+    SliceOf{{.InterfaceName}}{{.NameSuffix}} []{{.InterfacePrefix}}{{.InterfaceName}}{{.TypeArgs}}
+    {{end}}{{end}}
+
+    {{range .MapBindings}}{{if .Exposed}}
+    MapOf{{.InterfaceName}}{{.NameSuffix}} map[string]{{.InterfacePrefix}}{{.InterfaceName}}{{.TypeArgs}}
+    {{end}}{{end}}
+
+    {{range .Bindings}}{{if .FieldName}}
+    {{.FieldName}} {{.InterfacePrefix}}{{.InterfaceName}}
+    {{end}}{{end}}
+{{end}}
+
+{{define "qualifiedField"}}
+    {{.FieldName}} {{.ProvidedType}} ` + "`wire:\"-\"`" + `
+    qualified_{{.FieldName}} {{.QualifiedType}}
+{{end}}
+
+{{define "copies"}}
+    {{- range .Copies}}
+    c.{{.FieldName}} = ({{.ProvidedType}})(c.qualified_{{.FieldName}})
+    {{- end}}
+{{- end}}
+
+{{define "containerBuild"}}
         {{range .Providers}}
         {{.CallPrefix}}{{.ConstructorName}},
         {{end}}
         {{range .Prototypes}}
         {{.WrapperName}},
         {{end}}
+        {{range .Lazies}}
+        {{.WrapperName}},
+        {{end}}
         {{range .Bindings}}
         wire.Bind(new({{.InterfacePrefix}}{{.InterfaceName}}), new({{if .IsPointer}}*{{end}}{{.ComponentPrefix}}{{.StructName}})),
         {{end}}
         {{range .SliceBindings}}
-        ProvideSliceOf{{.InterfaceName}},
+        ProvideSliceOf{{.InterfaceName}}{{.NameSuffix}},
         {{end}}
-        wire.Struct(new(FloraContainer), "*"),
-    )
-    return nil, nil, nil
+        {{range .MapBindings}}
+        ProvideMapOf{{.InterfaceName}}{{.NameSuffix}},
+        {{end}}
+{{end}}
+
+{{define "lifecycle"}}
+{{if .Lifecycle}}
+// Start starts every flora.Starter in dependency order. If a component fails
+// to start, the components started before it are stopped again.
+func (c *{{.Container}}) Start(ctx context.Context) error {
+    var started []func(context.Context) error
+    {{- range .Lifecycle}}
+    {{- if .IsStarter}}
+    if err := {{.Receiver}}.Start(ctx); err != nil {
+        return errors.Join(fmt.Errorf("failed to start {{.Name}}: %w", err), floraStopAll(ctx, started))
+    }
+    {{- end}}
+    {{- if .IsStopper}}
+    started = append(started, {{.Receiver}}.Stop)
+    {{- end}}
+    {{- end}}
+    return nil
 }
+
+// Stop stops every flora.Stopper in reverse dependency order and returns
+// the errors of all components that failed to stop.
+func (c *{{.Container}}) Stop(ctx context.Context) error {
+    return floraStopAll(ctx, []func(context.Context) error{
+        {{- range .Lifecycle}}
+        {{- if .IsStopper}}
+        {{.Receiver}}.Stop,
+        {{- end}}
+        {{- end}}
+    })
+}
+{{else}}
+// Start is a no-op, no component implements flora.Starter.
+func (c *{{.Container}}) Start(ctx context.Context) error {
+    return nil
+}
+
+// Stop is a no-op, no component implements flora.Stopper.
+func (c *{{.Container}}) Stop(ctx context.Context) error {
+    return nil
+}
+{{end}}
+{{end}}
 `
 
 type providerData struct {
-	StructName      string
+	FieldName       string
+	FieldType       string
 	CallPrefix      string
-	TypePrefix      string
 	ConstructorName string
-	IsPointer       bool
+	ConstructorCall string
+	QualifiedType   string
+	ProvidedType    string
+	Params          []paramData
+	HasCleanup      bool
+	HasError        bool
+	IsWrapper       bool
+	owner           *engine.ComponentMetadata
 }
 
 type paramData struct {
 	Name string
 	Type string
+	Arg  string
 }
 
 type prototypeData struct {
@@ -161,22 +717,81 @@ type prototypeData struct {
 	FieldName       string
 	ConstructorCall string
 	ReturnType      string
+	QualifiedType   string
+	ProvidedType    string
+	Params          []paramData
+	HasCleanup      bool
+	HasError        bool
+	IsConfig        bool
+	owner           *engine.ComponentMetadata
+	// iface is the key of the interface a factory returns the component as
+	iface string
+}
+
+type lazyData struct {
+	WrapperName     string
+	FieldName       string
+	ConstructorCall string
+	ReturnType      string
+	QualifiedType   string
+	ProvidedType    string
+	ConfigReceiver  string
 	Params          []paramData
+	Args            []paramData
 	HasCleanup      bool
 	HasError        bool
 	IsConfig        bool
+	owner           *engine.ComponentMetadata
+}
+
+type scopedData struct {
+	WrapperName     string
+	FieldName       string
+	FieldType       string
+	ConstructorCall string
+	QualifiedType   string
+	ProvidedType    string
+	ConfigReceiver  string
+	Params          []paramData
+	Args            []string
+	HasCleanup      bool
+	HasError        bool
+	IsConfig        bool
+}
+
+type lifecycleData struct {
+	Name      string
+	Receiver  string
+	IsStarter bool
+	IsStopper bool
+	owner     *engine.ComponentMetadata
 }
 
 type configWrapperData struct {
-	WrapperName         string
-	ConfigPackagePrefix string
-	ConfigStructName    string
-	ConfigMethodName    string
-	ReturnType          string
-	Params              []paramData
-	HasCleanup          bool
-	HasError            bool
-	IsPrototype         bool
+	WrapperName      string
+	ConfigReceiver   string
+	ConfigMethodName string
+	ReturnType       string
+	QualifiedType    string
+	Params           []paramData
+	Args             []paramData
+	HasCleanup       bool
+	HasError         bool
+	IsPrototype      bool
+}
+
+// copyData is a named component the container exposes as the type it provides.
+// Wire fills the field 'qualified_<FieldName>' of the distinct qualified type,
+// which Initialize copies into the field.
+type copyData struct {
+	FieldName    string
+	ProvidedType string
+}
+
+type qualifierData struct {
+	TypeName     string
+	Underlying   string
+	ProvidedType string
 }
 
 type bindingData struct {
@@ -185,28 +800,73 @@ type bindingData struct {
 	ComponentPrefix string
 	StructName      string
 	IsPointer       bool
+	// FieldName is set for explicit bindings exposed on the container
+	FieldName string
+	owner     *engine.ComponentMetadata
+	iface     string
 }
 
-type sliceImplData struct {
-	ParamName  string
-	TypePrefix string
-	StructName string
+// injectedData describes the generated constructor of a component using field injection
+type injectedData struct {
+	ConstructorName string
+	StructType      string
+	Params          []paramData
+	Fields          []string
+}
+
+type multiImplData struct {
+	ParamName string
+	ParamType string
+	Arg       string
+	Key       string
 }
 
 type sliceBindingData struct {
 	InterfacePrefix string
 	InterfaceName   string
-	Implementations []sliceImplData
+	TypeArgs        string
+	NameSuffix      string
+	Implementations []multiImplData
+	// Exposed collections are fields of the container
+	Exposed bool
+	field   string
+	impls   []*engine.ComponentMetadata
 }
 
 type templateData struct {
 	PackageName    string
 	Imports        []string
+	Injected       []injectedData
 	Providers      []providerData
 	Prototypes     []prototypeData
+	Lazies         []lazyData
+	Scoped         []scopedData
+	ScopedBindings []bindingData
+	Lifecycle      []lifecycleData
 	ConfigWrappers []configWrapperData
 	Bindings       []bindingData
 	SliceBindings  []sliceBindingData
+	MapBindings    []sliceBindingData
+	Qualifiers     []qualifierData
+	Container      string
+	Modules        []moduleData
+	Copies         []copyData
+	ScopedCopies   []copyData
+}
+
+// moduleData holds the part of the graph a module container is built from,
+// its fields are named like the ones of templateData to share the templates
+type moduleData struct {
+	Name          string
+	Container     string
+	Providers     []providerData
+	Prototypes    []prototypeData
+	Lazies        []lazyData
+	Bindings      []bindingData
+	SliceBindings []sliceBindingData
+	MapBindings   []sliceBindingData
+	Lifecycle     []lifecycleData
+	Copies        []copyData
 }
 
 func (g *WireGenerator) Generate(outDir string, genCtx *engine.GeneratorContext) error {
@@ -247,14 +907,46 @@ func (g *WireGenerator) Generate(outDir string, genCtx *engine.GeneratorContext)
 
 	data := templateData{
 		PackageName: pkgName,
+		Container:   "FloraContainer",
 	}
 
+	var injected []injectedData
 	var providers []providerData
 	var prototypes []prototypeData
+	var lazies []lazyData
+	var scoped []scopedData
+	var scopedBindings []bindingData
 	var configWrappers []configWrapperData
 	var bindings []bindingData
 	importSet := make(map[string]bool)
 
+	qualifiers := make(map[string]qualifierData)
+	for _, comp := range genCtx.Components {
+		if comp.Qualifier == "" {
+			continue
+		}
+		retType := comp.StructName + typeArgList(comp.TypeArgs, pkgName, importSet)
+		if comp.PackageName != pkgName && !isBuiltInType(comp.StructName) {
+			retType = comp.PackageName + "." + retType
+		}
+		if comp.IsPointer {
+			retType = "*" + retType
+		}
+		provided := retType
+		switch comp.Scope {
+		case "prototype":
+			provided = factoryType(retType, comp.HasCleanup, comp.HasError)
+		case "lazy":
+			provided = "*flora.Lazy[" + retType + "]"
+			importSet[floraPkgPath] = true
+		}
+		qualifiers[comp.Qualifier] = qualifierData{
+			TypeName:     qualifiedTypeName(comp.Qualifier),
+			Underlying:   provided,
+			ProvidedType: provided,
+		}
+	}
+
 	for _, comp := range genCtx.Components {
 		isConfig := comp.ConfigStructName != ""
 		isBuiltIn := isBuiltInType(comp.StructName)
@@ -268,6 +960,19 @@ func (g *WireGenerator) Generate(outDir string, genCtx *engine.GeneratorContext)
 			importSet[comp.PackagePath] = true
 		}
 
+		// Provider funcs are called in the package that declares them
+		funcPrefix := compPrefix
+		if comp.ProviderPackagePath != "" {
+			funcPrefix = ""
+			if comp.ProviderPackageName != pkgName {
+				if comp.ProviderPackageName == "main" {
+					return errs.Wrap(ErrMainComponentLeak, "cannot generate container because provider func '%s' belongs to package 'main'.", comp.ConstructorName)
+				}
+				funcPrefix = comp.ProviderPackageName + "."
+				importSet[comp.ProviderPackagePath] = true
+			}
+		}
+
 		configPkgPrefix := ""
 		if isConfig {
 			if comp.ConfigPackageName != pkgName {
@@ -279,51 +984,213 @@ func (g *WireGenerator) Generate(outDir string, genCtx *engine.GeneratorContext)
 			}
 		}
 
+		hasQualifiedParams := false
 		var pData []paramData
 		for _, p := range comp.Params {
+			if p.Qualifier != "" {
+				q, ok := qualifiers[p.Qualifier]
+				if !ok {
+					return errs.Wrap(scanner.ErrUnknownQualifier, "component '%s' requests qualifier '%s', but no component has that name", comp.StructName, p.Qualifier)
+				}
+				hasQualifiedParams = true
+				pData = append(pData, paramData{Name: p.Name, Type: q.TypeName, Arg: "(" + q.ProvidedType + ")(" + p.Name + ")"})
+				continue
+			}
 			for _, imp := range p.Imports {
 				importSet[imp] = true
 			}
-			pType := p.Type
-			pType = strings.ReplaceAll(pType, "*"+pkgName+".", "*")
-			pType = strings.ReplaceAll(pType, "[]"+pkgName+".", "[]")
-			if after, ok := strings.CutPrefix(pType, pkgName+"."); ok {
-				pType = after
-			}
-			pData = append(pData, paramData{Name: p.Name, Type: pType})
+			pData = append(pData, paramData{Name: p.Name, Type: localizeType(p.Type, pkgName), Arg: p.Name})
 		}
 
-		retType := compPrefix + comp.StructName
+		// Methods of a Configuration with a constructor are called on the shared
+		// instance, which is passed in as the first parameter
+		configReceiver := configPkgPrefix + comp.ConfigStructName + "{}"
+		args := pData
+		if comp.ConfigInstance {
+			configReceiver = pData[0].Arg
+			args = pData[1:]
+		}
+
+		typeArgs := typeArgList(comp.TypeArgs, pkgName, importSet)
+		identName := comp.StructName + typeArgSuffix(comp.TypeArgs)
+
+		retType := compPrefix + comp.StructName + typeArgs
 		if comp.IsPointer {
 			retType = "*" + retType
 		}
 
-		if comp.Scope == "prototype" {
-			wrapperName := "ProvidePrototype" + comp.StructName
+		constructorCall := funcPrefix + comp.ConstructorName
+		if !isConfig && comp.ProviderPackagePath == "" {
+			constructorCall += typeArgs
+		}
+
+		if len(comp.InjectFields) > 0 {
+			constructorCall = comp.ConstructorName
+			var params []paramData
+			for i, p := range comp.Params {
+				params = append(params, paramData{Name: fmt.Sprintf("p%d", i), Type: localizeType(p.Type, pkgName)})
+				for _, imp := range p.Imports {
+					importSet[imp] = true
+				}
+			}
+			injected = append(injected, injectedData{
+				ConstructorName: comp.ConstructorName,
+				StructType:      compPrefix + comp.StructName,
+				Params:          params,
+				Fields:          comp.InjectFields,
+			})
+		}
+
+		qualifiedType, providedType := "", ""
+		if comp.Qualifier != "" {
+			qualifiedType = qualifiedTypeName(comp.Qualifier)
+			providedType = qualifiers[comp.Qualifier].ProvidedType
+		}
+
+		if comp.Scope == "request" {
+			importSet["context"] = true
+			wrapperName := "ProvideScoped_" + identName
+			fieldType := retType
+			if comp.Qualifier != "" {
+				wrapperName = "ProvideScoped_" + qualifierIdent(comp.Qualifier)
+				fieldType = qualifiedType
+			}
+			call := constructorCall
+			if isConfig {
+				call = "cfg." + comp.ConfigMethodName
+			}
+
+			var params []paramData
+			var args []string
+			needsParent, needsContext := false, false
+			for i, p := range comp.Params {
+				if comp.ConfigInstance && i == 0 && p.Source == engine.SourceComponent {
+					needsParent = true
+					configReceiver = parentFieldExpr(p)
+					continue
+				}
+				switch {
+				case p.Source == engine.SourceContext:
+					needsContext = true
+					args = append(args, "ctx")
+				case p.Source == engine.SourceSlice || p.Source == engine.SourceMap || p.Source == engine.SourceFactory ||
+					(p.Source == engine.SourceComponent && p.Providers[0].Scope != "request"):
+					needsParent = true
+					args = append(args, parentFieldExpr(p))
+				default:
+					params = append(params, pData[i])
+					args = append(args, pData[i].Arg)
+				}
+			}
+			if needsParent {
+				params = append([]paramData{{Name: "parent", Type: "*FloraContainer"}}, params...)
+			}
+			if needsContext {
+				params = append([]paramData{{Name: "ctx", Type: "context.Context"}}, params...)
+			}
+
+			scoped = append(scoped, scopedData{
+				WrapperName:     wrapperName,
+				FieldName:       containerFieldName(comp),
+				FieldType:       fieldType,
+				ConstructorCall: call,
+				QualifiedType:   qualifiedType,
+				ProvidedType:    providedType,
+				ConfigReceiver:  configReceiver,
+				Params:          params,
+				Args:            args,
+				HasCleanup:      comp.HasCleanup,
+				HasError:        comp.HasError,
+				IsConfig:        isConfig,
+			})
+
+			for _, iface := range comp.Implements {
+				ifacePrefix := ""
+				if iface.PackageName != pkgName {
+					if iface.PackageName == "main" {
+						return errs.Wrap(ErrMainInterfaceLeak, "cannot generate container in package '%s' because interface '%s' belongs to package 'main'. Change output dir (-o) to your main directory or move the interface.", pkgName, iface.InterfaceName)
+					}
+					ifacePrefix = iface.PackageName + "."
+					importSet[iface.PackagePath] = true
+				}
+
+				binding := bindingData{
+					InterfacePrefix: ifacePrefix,
+					InterfaceName:   iface.InterfaceName + typeArgList(iface.TypeArgs, pkgName, importSet),
+					ComponentPrefix: compPrefix,
+					StructName:      comp.StructName + typeArgs,
+					IsPointer:       comp.IsPointer,
+					owner:           comp,
+					iface:           iface.Key(),
+				}
+				if iface.Explicit {
+					binding.FieldName = iface.InterfaceName + typeArgSuffix(iface.TypeArgs)
+				}
+				scopedBindings = append(scopedBindings, binding)
+			}
+		} else if comp.Scope == "lazy" {
+			importSet[floraPkgPath] = true
+			wrapperName := "ProvideLazy_" + identName
+			fieldName := containerFieldName(comp)
+			if comp.Qualifier != "" {
+				wrapperName = "ProvideLazy_" + qualifierIdent(comp.Qualifier)
+			}
+			call := constructorCall
+			if isConfig {
+				call = "cfg." + comp.ConfigMethodName
+			}
+
+			lazies = append(lazies, lazyData{
+				WrapperName:     wrapperName,
+				FieldName:       fieldName,
+				ConstructorCall: call,
+				ReturnType:      retType,
+				QualifiedType:   qualifiedType,
+				ProvidedType:    providedType,
+				ConfigReceiver:  configReceiver,
+				Params:          pData,
+				Args:            args,
+				HasCleanup:      comp.HasCleanup,
+				HasError:        comp.HasError,
+				IsConfig:        isConfig,
+				owner:           comp,
+			})
+		} else if comp.Scope == "prototype" {
+			wrapperName := "ProvidePrototype" + identName
+			fieldName := containerFieldName(comp)
+			if comp.Qualifier != "" {
+				wrapperName = "ProvidePrototype_" + qualifierIdent(comp.Qualifier)
+			}
 			if isConfig {
-				wrapperName = "ProvidePrototype_" + comp.ConfigStructName + "_" + comp.ConfigMethodName
+				if comp.Qualifier == "" {
+					wrapperName = "ProvidePrototype_" + comp.ConfigStructName + "_" + comp.ConfigMethodName
+				}
 				configWrappers = append(configWrappers, configWrapperData{
-					WrapperName:         wrapperName,
-					ConfigPackagePrefix: configPkgPrefix,
-					ConfigStructName:    comp.ConfigStructName,
-					ConfigMethodName:    comp.ConfigMethodName,
-					ReturnType:          retType,
-					Params:              pData,
-					HasCleanup:          comp.HasCleanup,
-					HasError:            comp.HasError,
-					IsPrototype:         true,
+					WrapperName:      wrapperName,
+					ConfigReceiver:   configReceiver,
+					ConfigMethodName: comp.ConfigMethodName,
+					ReturnType:       retType,
+					QualifiedType:    qualifiedType,
+					Params:           pData,
+					Args:             args,
+					HasCleanup:       comp.HasCleanup,
+					HasError:         comp.HasError,
+					IsPrototype:      true,
 				})
 			}
 
 			prototypes = append(prototypes, prototypeData{
 				WrapperName:     wrapperName,
-				FieldName:       comp.StructName + "Factory",
-				ConstructorCall: compPrefix + comp.ConstructorName,
+				FieldName:       fieldName,
+				ConstructorCall: constructorCall,
 				ReturnType:      retType,
+				QualifiedType:   qualifiedType,
+				ProvidedType:    providedType,
 				Params:          pData,
 				HasCleanup:      comp.HasCleanup,
 				HasError:        comp.HasError,
 				IsConfig:        isConfig,
+				owner:           comp,
 			})
 
 			for _, iface := range comp.Implements {
@@ -337,42 +1204,70 @@ func (g *WireGenerator) Generate(outDir string, genCtx *engine.GeneratorContext)
 				}
 
 				prototypes = append(prototypes, prototypeData{
-					WrapperName:     "ProvidePrototype" + comp.StructName + "As" + iface.InterfaceName,
-					FieldName:       iface.InterfaceName + "Factory",
-					ConstructorCall: compPrefix + comp.ConstructorName,
-					ReturnType:      ifacePrefix + iface.InterfaceName,
+					WrapperName:     "ProvidePrototype" + identName + "As" + iface.InterfaceName + typeArgSuffix(iface.TypeArgs),
+					FieldName:       iface.InterfaceName + typeArgSuffix(iface.TypeArgs) + "Factory",
+					ConstructorCall: constructorCall,
+					ReturnType:      ifacePrefix + iface.InterfaceName + typeArgList(iface.TypeArgs, pkgName, importSet),
 					Params:          pData,
 					HasCleanup:      comp.HasCleanup,
 					HasError:        comp.HasError,
 					IsConfig:        isConfig,
+					owner:           comp,
+					iface:           iface.Key(),
 				})
 			}
 
 		} else {
 			wrapperName := comp.ConstructorName
-			callPrefix := compPrefix
+			callPrefix := funcPrefix
+			fieldName := containerFieldName(comp)
+			fieldType := retType
+			isWrapper := false
+
+			if comp.Qualifier != "" {
+				fieldType = qualifiedType
+			}
+
+			if len(comp.InjectFields) > 0 {
+				callPrefix = ""
+			}
 
 			if isConfig {
 				callPrefix = ""
 				configWrappers = append(configWrappers, configWrapperData{
-					WrapperName:         wrapperName,
-					ConfigPackagePrefix: configPkgPrefix,
-					ConfigStructName:    comp.ConfigStructName,
-					ConfigMethodName:    comp.ConfigMethodName,
-					ReturnType:          retType,
-					Params:              pData,
-					HasCleanup:          comp.HasCleanup,
-					HasError:            comp.HasError,
-					IsPrototype:         false,
+					WrapperName:      wrapperName,
+					ConfigReceiver:   configReceiver,
+					ConfigMethodName: comp.ConfigMethodName,
+					ReturnType:       retType,
+					QualifiedType:    qualifiedType,
+					Params:           pData,
+					Args:             args,
+					HasCleanup:       comp.HasCleanup,
+					HasError:         comp.HasError,
+					IsPrototype:      false,
 				})
+			} else if comp.Qualifier != "" || hasQualifiedParams || len(comp.TypeArgs) > 0 {
+				isWrapper = true
+				callPrefix = ""
+				wrapperName = "Provide_" + identName
+				if comp.Qualifier != "" {
+					wrapperName = "Provide_" + qualifierIdent(comp.Qualifier)
+				}
 			}
 
 			providers = append(providers, providerData{
-				StructName:      comp.StructName,
+				FieldName:       fieldName,
+				FieldType:       fieldType,
 				CallPrefix:      callPrefix,
-				TypePrefix:      compPrefix,
 				ConstructorName: wrapperName,
-				IsPointer:       comp.IsPointer,
+				ConstructorCall: constructorCall,
+				QualifiedType:   qualifiedType,
+				ProvidedType:    providedType,
+				Params:          pData,
+				HasCleanup:      comp.HasCleanup,
+				HasError:        comp.HasError,
+				IsWrapper:       isWrapper,
+				owner:           comp,
 			})
 
 			for _, iface := range comp.Implements {
@@ -385,17 +1280,47 @@ func (g *WireGenerator) Generate(outDir string, genCtx *engine.GeneratorContext)
 					importSet[iface.PackagePath] = true
 				}
 
-				bindings = append(bindings, bindingData{
+				binding := bindingData{
 					InterfacePrefix: ifacePrefix,
-					InterfaceName:   iface.InterfaceName,
+					InterfaceName:   iface.InterfaceName + typeArgList(iface.TypeArgs, pkgName, importSet),
 					ComponentPrefix: compPrefix,
-					StructName:      comp.StructName,
+					StructName:      comp.StructName + typeArgs,
 					IsPointer:       comp.IsPointer,
-				})
+					owner:           comp,
+					iface:           iface.Key(),
+				}
+				if iface.Explicit {
+					binding.FieldName = iface.InterfaceName + typeArgSuffix(iface.TypeArgs)
+				}
+				bindings = append(bindings, binding)
 			}
 		}
 	}
 
+	// implementationData renders an implementation injected into a slice or map
+	implementationData := func(i int, impl *engine.ComponentMetadata) multiImplData {
+		paramName := fmt.Sprintf("p%d", i)
+		typePrefix := ""
+		if impl.IsPointer {
+			typePrefix = "*"
+		}
+		if impl.PackageName != pkgName && impl.PackageName != "main" {
+			typePrefix += impl.PackageName + "."
+			importSet[impl.PackagePath] = true
+		}
+		data := multiImplData{
+			ParamName: paramName,
+			ParamType: typePrefix + impl.StructName + typeArgList(impl.TypeArgs, pkgName, importSet),
+			Arg:       paramName,
+			Key:       strconv.Quote(impl.Key),
+		}
+		if q, ok := qualifiers[impl.Qualifier]; ok {
+			data.ParamType = q.TypeName
+			data.Arg = "(" + q.ProvidedType + ")(" + paramName + ")"
+		}
+		return data
+	}
+
 	var sliceBindingsData []sliceBindingData
 	for _, sb := range genCtx.SliceBindings {
 		slices.SortFunc(sb.Implementations, func(a, b *engine.ComponentMetadata) int {
@@ -407,35 +1332,172 @@ func (g *WireGenerator) Generate(outDir string, genCtx *engine.GeneratorContext)
 			importSet[sb.Interface.PackagePath] = true
 		}
 
-		var impls []sliceImplData
+		var impls []multiImplData
 		for i, impl := range sb.Implementations {
-			typePrefix := ""
-			if impl.IsPointer {
-				typePrefix = "*"
-			}
-			if impl.PackageName != pkgName && impl.PackageName != "main" {
-				typePrefix += impl.PackageName + "."
-				importSet[impl.PackagePath] = true
-			}
-			impls = append(impls, sliceImplData{
-				ParamName:  fmt.Sprintf("p%d", i),
-				TypePrefix: typePrefix,
-				StructName: impl.StructName,
-			})
+			impls = append(impls, implementationData(i, impl))
 		}
 
 		sliceBindingsData = append(sliceBindingsData, sliceBindingData{
 			InterfacePrefix: ifacePrefix,
 			InterfaceName:   sb.Interface.InterfaceName,
+			TypeArgs:        typeArgList(sb.Interface.TypeArgs, pkgName, importSet),
+			NameSuffix:      typeArgSuffix(sb.Interface.TypeArgs),
 			Implementations: impls,
+			field:           "SliceOf" + sb.Interface.InterfaceName + typeArgSuffix(sb.Interface.TypeArgs),
+			impls:           sb.Implementations,
 		})
 	}
 
+	var mapBindingsData []sliceBindingData
+	for _, mb := range genCtx.MapBindings {
+		slices.SortFunc(mb.Implementations, func(a, b *engine.ComponentMetadata) int {
+			return cmp.Compare(a.Key, b.Key)
+		})
+		ifacePrefix := ""
+		if mb.Interface.PackageName != pkgName && mb.Interface.PackageName != "main" {
+			ifacePrefix = mb.Interface.PackageName + "."
+			importSet[mb.Interface.PackagePath] = true
+		}
+
+		var impls []multiImplData
+		for i, impl := range mb.Implementations {
+			impls = append(impls, implementationData(i, impl))
+		}
+
+		mapBindingsData = append(mapBindingsData, sliceBindingData{
+			InterfacePrefix: ifacePrefix,
+			InterfaceName:   mb.Interface.InterfaceName,
+			TypeArgs:        typeArgList(mb.Interface.TypeArgs, pkgName, importSet),
+			NameSuffix:      typeArgSuffix(mb.Interface.TypeArgs),
+			Implementations: impls,
+			field:           "MapOf" + mb.Interface.InterfaceName + typeArgSuffix(mb.Interface.TypeArgs),
+			impls:           mb.Implementations,
+		})
+	}
+
+	for _, q := range qualifiers {
+		data.Qualifiers = append(data.Qualifiers, q)
+	}
+	slices.SortFunc(data.Qualifiers, func(a, b qualifierData) int {
+		return cmp.Compare(a.TypeName, b.TypeName)
+	})
+
+	// Exposed interfaces are named after the interface, prefixed by its package on a clash
+	fieldNames := make(map[string]bool)
+	for _, p := range providers {
+		fieldNames[p.FieldName] = true
+	}
+	for _, p := range prototypes {
+		fieldNames[p.FieldName] = true
+	}
+	for _, l := range lazies {
+		fieldNames[l.FieldName] = true
+	}
+	for _, s := range scoped {
+		fieldNames[s.FieldName] = true
+	}
+	for _, b := range [][]bindingData{bindings, scopedBindings} {
+		for i := range b {
+			if b[i].FieldName == "" {
+				continue
+			}
+			if fieldNames[b[i].FieldName] {
+				ifacePkg := strings.TrimSuffix(b[i].InterfacePrefix, ".")
+				if ifacePkg == "" {
+					ifacePkg = pkgName
+				}
+				b[i].FieldName = exportedName(ifacePkg) + b[i].FieldName
+			}
+			fieldNames[b[i].FieldName] = true
+		}
+	}
+
+	data.Injected = injected
 	data.Providers = providers
 	data.Prototypes = prototypes
+	data.Lazies = lazies
+	data.Scoped = scoped
+
+	importSet["context"] = true
+	for _, comp := range genCtx.Lifecycle {
+		importSet["errors"] = true
+		importSet["fmt"] = true
+		data.Lifecycle = append(data.Lifecycle, lifecycleData{
+			Name:      containerFieldName(comp),
+			Receiver:  "c." + containerFieldName(comp),
+			IsStarter: comp.IsStarter,
+			IsStopper: comp.IsStopper,
+			owner:     comp,
+		})
+	}
+	data.ScopedBindings = scopedBindings
 	data.ConfigWrappers = configWrappers
 	data.Bindings = bindings
 	data.SliceBindings = sliceBindingsData
+	data.MapBindings = mapBindingsData
+
+	exposeAll := genCtx.Expose != "roots-only"
+	isLifecycle := make(map[*engine.ComponentMetadata]bool)
+	for _, comp := range genCtx.Lifecycle {
+		isLifecycle[comp] = true
+	}
+
+	for _, module := range genCtx.Modules {
+		var fields []*engine.ComponentMetadata
+		for _, comp := range module.Components {
+			if !comp.Hidden || isLifecycle[comp] || slices.Contains(comp.Modules, module.Name) {
+				fields = append(fields, comp)
+			}
+		}
+		md := narrowContainer(fields, nil, exposeAll, data)
+		md.Name = module.Name
+		md.Container = exportedName(module.Name) + "Container"
+		data.Modules = append(data.Modules, md)
+	}
+
+	// Hidden components stay fields when lifecycle hooks or the request scope read them
+	var fields []*engine.ComponentMetadata
+	exposed := make(map[string]bool)
+	for _, comp := range genCtx.Components {
+		if comp.Scope != "request" {
+			if !comp.Hidden || isLifecycle[comp] {
+				fields = append(fields, comp)
+			}
+			continue
+		}
+		fields = append(fields, comp)
+		for _, p := range comp.Params {
+			if field := collectionFieldName(p); field != "" {
+				exposed[field] = true
+			} else if len(p.Providers) > 0 && p.Providers[0].Scope != "request" {
+				fields = append(fields, p.Providers[0])
+			}
+		}
+	}
+	if exposeAll {
+		for _, sb := range slices.Concat(data.SliceBindings, data.MapBindings) {
+			exposed[sb.field] = true
+		}
+		for _, p := range data.Prototypes {
+			if p.iface != "" {
+				exposed[p.FieldName] = true
+			}
+		}
+	}
+	root := narrowContainer(fields, exposed, exposeAll, data)
+	data.Providers = root.Providers
+	data.Prototypes = root.Prototypes
+	data.Lazies = root.Lazies
+	data.Bindings = root.Bindings
+	data.SliceBindings = root.SliceBindings
+	data.MapBindings = root.MapBindings
+	data.Lifecycle = root.Lifecycle
+	data.Copies = root.Copies
+	for _, s := range data.Scoped {
+		if s.QualifiedType != "" {
+			data.ScopedCopies = append(data.ScopedCopies, copyData{FieldName: s.FieldName, ProvidedType: s.ProvidedType})
+		}
+	}
 
 	for imp := range importSet {
 		if generatedPkgPath != "" && imp == generatedPkgPath {
@@ -457,14 +1519,30 @@ func (g *WireGenerator) Generate(outDir string, genCtx *engine.GeneratorContext)
 		return errs.Wrap(chainErr, "failed to apply data to template")
 	}
 
+	injectorSrc, genericsSrc, err := splitGenericDecls(tempFilePath, buf.Bytes())
+	if err != nil {
+		chainErr := fmt.Errorf("%w: %w", ErrGenericDecls, err)
+		return errs.Wrap(chainErr, "path: %s", tempFilePath)
+	}
+
+	genericsFilePath := filepath.Join(absOutDir, "flora_injector_generics.go")
+	if genericsSrc != nil {
+		log.Debug("Writing generic declarations wire cannot copy", "path", genericsFilePath)
+		if err := os.WriteFile(genericsFilePath, genericsSrc, 0644); err != nil {
+			chainErr := fmt.Errorf("%w: %w", ErrWriteTempFile, err)
+			return errs.Wrap(chainErr, "path: %s", genericsFilePath)
+		}
+	}
+
 	log.Debug("Writing temporary wire template", "path", tempFilePath)
-	if err := os.WriteFile(tempFilePath, buf.Bytes(), 0644); err != nil {
+	if err := os.WriteFile(tempFilePath, injectorSrc, 0644); err != nil {
 		chainErr := fmt.Errorf("%w: %w", ErrWriteTempFile, err)
 		return errs.Wrap(chainErr, "path: %s", tempFilePath)
 	}
 
 	defer func() {
 		os.Remove(tempFilePath)
+		os.Remove(genericsFilePath)
 		tidyCmd := exec.Command("go", "mod", "tidy")
 		tidyCmd.Dir = absOutDir
 		_ = tidyCmd.Run()
@@ -478,8 +1556,46 @@ func (g *WireGenerator) Generate(outDir string, genCtx *engine.GeneratorContext)
 		return errs.Wrap(chainErr, "failed running 'go get github.com/google/wire@latest' in %s", absOutDir)
 	}
 
-	log.Debug("Running DI engine via Google Wire...")
-	cmd := exec.Command("go", "run", "github.com/google/wire/cmd/wire@latest", "gen", ".")
+	log.Debug("Running DI engine via Google Wire...", "tags", genCtx.Tags, "goos", genCtx.GOOS, "goarch", genCtx.GOARCH)
+	var wireArgs []string
+	if len(genCtx.Tags) > 0 {
+		wireArgs = append(wireArgs, "-tags", strings.Join(genCtx.Tags, " "))
+	}
+	wireArgs = append(wireArgs, ".")
+
+	var cmd *exec.Cmd
+	if genCtx.GOOS == "" && genCtx.GOARCH == "" {
+		cmd = exec.Command("go", append([]string{"run", "github.com/google/wire/cmd/wire@latest", "gen"}, wireArgs...)...)
+	} else {
+		// 'go run' would cross-compile wire itself for the target platform,
+		// so wire is installed for the host and only its package loading
+		// sees the target GOOS/GOARCH.
+		binDir, err := os.MkdirTemp("", "flora_wire_*")
+		if err != nil {
+			chainErr := fmt.Errorf("%w: %w", ErrWireExecution, err)
+			return errs.Wrap(chainErr, "failed to create temporary directory for wire")
+		}
+		defer os.RemoveAll(binDir)
+
+		installCmd := exec.Command("go", "install", "github.com/google/wire/cmd/wire@latest")
+		installCmd.Dir = absOutDir
+		installCmd.Env = append(os.Environ(), "GOBIN="+binDir)
+		var installStderr bytes.Buffer
+		installCmd.Stderr = &installStderr
+		if err := installCmd.Run(); err != nil {
+			chainErr := fmt.Errorf("%w: %w", ErrWireExecution, err)
+			return errs.Wrap(chainErr, "stderr:\n%s", installStderr.String())
+		}
+
+		cmd = exec.Command(filepath.Join(binDir, "wire"), append([]string{"gen"}, wireArgs...)...)
+		cmd.Env = os.Environ()
+		if genCtx.GOOS != "" {
+			cmd.Env = append(cmd.Env, "GOOS="+genCtx.GOOS)
+		}
+		if genCtx.GOARCH != "" {
+			cmd.Env = append(cmd.Env, "GOARCH="+genCtx.GOARCH)
+		}
+	}
 	cmd.Dir = absOutDir
 
 	var stderr bytes.Buffer
@@ -491,7 +1607,7 @@ func (g *WireGenerator) Generate(outDir string, genCtx *engine.GeneratorContext)
 	}
 
 	generatedWireFile := filepath.Join(absOutDir, "wire_gen.go")
-	finalFloraFile := filepath.Join(absOutDir, "flora_container.go")
+	finalFloraFile := filepath.Join(absOutDir, containerFileName(genCtx))
 
 	log.Debug("Renaming generated file", "from", generatedWireFile, "to", finalFloraFile)
 	if err := os.Rename(generatedWireFile, finalFloraFile); err != nil {
@@ -499,5 +1615,250 @@ func (g *WireGenerator) Generate(outDir string, genCtx *engine.GeneratorContext)
 		return errs.Wrap(chainErr, "from %s to %s", generatedWireFile, finalFloraFile)
 	}
 
+	if genericsSrc != nil {
+		log.Debug("Appending generic declarations to generated file", "path", finalFloraFile)
+		if err := appendGenericDecls(finalFloraFile, genericsSrc); err != nil {
+			chainErr := fmt.Errorf("%w: %w", ErrGenericDecls, err)
+			return errs.Wrap(chainErr, "path: %s", finalFloraFile)
+		}
+	}
+
+	if constraint := buildConstraint(genCtx); constraint != "" {
+		log.Debug("Adding build constraint to generated file", "constraint", constraint)
+		if err := addBuildConstraint(finalFloraFile, constraint); err != nil {
+			chainErr := fmt.Errorf("%w: %w", ErrBuildConstraint, err)
+			return errs.Wrap(chainErr, "path: %s", finalFloraFile)
+		}
+	}
+
+	if err := excludeOtherContainers(absOutDir); err != nil {
+		chainErr := fmt.Errorf("%w: %w", ErrBuildConstraint, err)
+		return errs.Wrap(chainErr, "path: %s", filepath.Join(absOutDir, "flora_container.go"))
+	}
+
+	return nil
+}
+
+// constraintTerms returns the profile, build tags and target platform the
+// container was generated for
+func constraintTerms(genCtx *engine.GeneratorContext) []string {
+	var terms []string
+	if genCtx.Profile != "" {
+		terms = append(terms, genCtx.Profile)
+	}
+	terms = append(terms, genCtx.Tags...)
+	if genCtx.GOOS != "" {
+		terms = append(terms, genCtx.GOOS)
+	}
+	if genCtx.GOARCH != "" {
+		terms = append(terms, genCtx.GOARCH)
+	}
+	return terms
+}
+
+// containerFileName returns the name of the generated container file.
+// Every profile and tag set gets its own file so that containers can live
+// side by side. The terms are joined with dots, because Go would treat
+// '_test' or '_<GOOS>' file name suffixes as implicit constraints.
+func containerFileName(genCtx *engine.GeneratorContext) string {
+	terms := constraintTerms(genCtx)
+	if len(terms) == 0 {
+		return "flora_container.go"
+	}
+	return "flora_container." + strings.Join(terms, ".") + ".go"
+}
+
+// buildConstraint returns the build constraint the generated container must
+// carry in addition to '!wireinject', or an empty string if there is none
+func buildConstraint(genCtx *engine.GeneratorContext) string {
+	return strings.Join(constraintTerms(genCtx), " && ")
+}
+
+// addBuildConstraint extends the '!wireinject' constraint written by wire
+func addBuildConstraint(path, constraint string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	src := string(content)
+	src = strings.Replace(src, "//go:build !wireinject\n", "//go:build !wireinject && ("+constraint+")\n", 1)
+	src = strings.Replace(src, "// +build !wireinject\n", "", 1)
+
+	return os.WriteFile(path, []byte(src), 0644)
+}
+
+// excludeOtherContainers keeps the default container out of the builds of the
+// profile and tag specific containers next to it, which declare the same
+// functions. Its constraint is rebuilt from all of them, so the containers can
+// be generated in any order.
+func excludeOtherContainers(dir string) error {
+	defaultFile := filepath.Join(dir, "flora_container.go")
+	content, err := os.ReadFile(defaultFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	others, err := filepath.Glob(filepath.Join(dir, "flora_container.*.go"))
+	if err != nil {
+		return err
+	}
+
+	var expr constraint.Expr = &constraint.NotExpr{X: &constraint.TagExpr{Tag: "wireinject"}}
+	for _, other := range others {
+		otherContent, err := os.ReadFile(other)
+		if err != nil {
+			return err
+		}
+		if terms := containerConstraint(string(otherContent)); terms != nil {
+			expr = &constraint.AndExpr{X: expr, Y: &constraint.NotExpr{X: terms}}
+		}
+	}
+
+	src := regexp.MustCompile(`(?m)^//go:build .*$`).ReplaceAllLiteralString(string(content), "//go:build "+expr.String())
+	src = strings.Replace(src, "// +build !wireinject\n", "", 1)
+
+	return os.WriteFile(defaultFile, []byte(src), 0644)
+}
+
+// containerConstraint returns the terms addBuildConstraint added to the
+// '!wireinject' constraint of a container, or nil if it has none
+func containerConstraint(src string) constraint.Expr {
+	for line := range strings.Lines(src) {
+		if !constraint.IsGoBuild(line) {
+			continue
+		}
+		expr, err := constraint.Parse(line)
+		if err != nil {
+			return nil
+		}
+		if and, ok := expr.(*constraint.AndExpr); ok {
+			return and.Y
+		}
+		return nil
+	}
 	return nil
 }
+
+// splitGenericDecls moves every declaration that instantiates a generic type
+// with more than one type argument out of the injector. Wire copies all other
+// declarations of the injector into its output, but cannot copy these. They
+// are placed in a second 'wireinject' file instead and appended to the
+// container after wire ran. It returns nil for the second file if there is
+// nothing to move.
+func splitGenericDecls(path string, src []byte) ([]byte, []byte, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, src, parser.ParseComments)
+	if err != nil {
+		// wire reports syntax errors of the injector with more context
+		return src, nil, nil
+	}
+
+	var kept []ast.Decl
+	var moved bytes.Buffer
+	for _, decl := range file.Decls {
+		isGeneric, isInjector := false, false
+		ast.Inspect(decl, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.IndexListExpr:
+				isGeneric = true
+			case *ast.SelectorExpr:
+				if ident, ok := n.X.(*ast.Ident); ok && ident.Name == "wire" && n.Sel.Name == "Build" {
+					isInjector = true
+				}
+			}
+			return true
+		})
+
+		if !isGeneric || isInjector {
+			kept = append(kept, decl)
+			continue
+		}
+		moved.Write(src[fset.Position(decl.Pos()).Offset:fset.Position(decl.End()).Offset])
+		moved.WriteString("\n\n")
+	}
+
+	if moved.Len() == 0 {
+		return src, nil, nil
+	}
+
+	var header bytes.Buffer
+	header.WriteString("//go:build wireinject\n\npackage " + file.Name.Name + "\n\n")
+	for _, imp := range file.Imports {
+		header.WriteString("import ")
+		if imp.Name != nil {
+			header.WriteString(imp.Name.Name + " ")
+		}
+		header.WriteString(imp.Path.Value + "\n")
+	}
+
+	generics, err := imports.Process(path, append(header.Bytes(), moved.Bytes()...), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	file.Decls = kept
+	var injector bytes.Buffer
+	if err := format.Node(&injector, fset, file); err != nil {
+		return nil, nil, err
+	}
+
+	injectorSrc, err := imports.Process(path, injector.Bytes(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return injectorSrc, generics, nil
+}
+
+// appendGenericDecls appends the declarations split off by splitGenericDecls
+// to the generated container, together with the imports they need
+func appendGenericDecls(path string, genericsSrc []byte) error {
+	fset := token.NewFileSet()
+	container, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return err
+	}
+
+	genericsFset := token.NewFileSet()
+	generics, err := parser.ParseFile(genericsFset, "", genericsSrc, 0)
+	if err != nil {
+		return err
+	}
+
+	for _, imp := range generics.Imports {
+		importPath, err := strconv.Unquote(imp.Path.Value)
+		if err != nil {
+			return err
+		}
+		if imp.Name != nil {
+			astutil.AddNamedImport(fset, container, imp.Name.Name, importPath)
+		} else {
+			astutil.AddImport(fset, container, importPath)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, container); err != nil {
+		return err
+	}
+
+	for _, decl := range generics.Decls {
+		if genDecl, ok := decl.(*ast.GenDecl); ok && genDecl.Tok == token.IMPORT {
+			continue
+		}
+		buf.WriteString("\n")
+		buf.Write(genericsSrc[genericsFset.Position(decl.Pos()).Offset:genericsFset.Position(decl.End()).Offset])
+		buf.WriteString("\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, src, 0644)
+}
@@ -19,6 +19,7 @@ import (
 	"errors"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 	"testing"
 
@@ -29,11 +30,11 @@ import (
 func TestGenerate(t *testing.T) {
 
 	loadHappyComponents := func(t *testing.T) *engine.GeneratorContext {
-		packages, err := scanner.ScanPackages("testdata/happy")
+		packages, err := scanner.ScanPackages("testdata/happy", scanner.Options{})
 		if err != nil {
 			t.Fatalf("ScanPackages failed: %v", err)
 		}
-		genCtx, err := scanner.ParsePackages(packages)
+		genCtx, err := scanner.ParsePackages(packages, scanner.Options{})
 		if err != nil {
 			t.Fatalf("ParsePkgs failed: %v", err)
 		}
@@ -379,6 +380,26 @@ func TestGenerate(t *testing.T) {
 			},
 			expErr: ErrWireExecution,
 		},
+		{
+			name: "TestUnknownQualifier",
+			setupDir: func(t *testing.T) string {
+				return t.TempDir()
+			},
+			genCtx: &engine.GeneratorContext{
+				Components: []*engine.ComponentMetadata{
+					{
+						PackageName:     "otherpkg",
+						PackagePath:     "github.com/test/otherpkg",
+						StructName:      "Store",
+						ConstructorName: "NewStore",
+						Params: []engine.ParamMetadata{
+							{Name: "p0", Type: "*otherpkg.DB", Qualifier: "readDB"},
+						},
+					},
+				},
+			},
+			expErr: scanner.ErrUnknownQualifier,
+		},
 		{
 			name: "TestConfigInMainLeak",
 			setupDir: func(t *testing.T) string {
@@ -429,3 +450,295 @@ func TestGenerate(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateProfile(t *testing.T) {
+	packages, err := scanner.ScanPackages("testdata/happy", scanner.Options{})
+	if err != nil {
+		t.Fatalf("ScanPackages failed: %v", err)
+	}
+	genCtx, err := scanner.ParsePackages(packages, scanner.Options{Profile: "dev"})
+	if err != nil {
+		t.Fatalf("ParsePackages failed: %v", err)
+	}
+
+	tmpDir, err := os.MkdirTemp(".", "flora_test_out_*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := NewWireGenerator().Generate(tmpDir, genCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "flora_container.dev.go"))
+	if err != nil {
+		t.Fatalf("expected profile container file: %v", err)
+	}
+
+	if !strings.Contains(string(content), "//go:build !wireinject && (dev)") {
+		t.Errorf("expected build constraint for profile 'dev', got:\n%s", content)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, "flora_container.go")); !os.IsNotExist(err) {
+		t.Errorf("expected no default container file, got %v", err)
+	}
+}
+
+func TestGenerateExposeRootsOnly(t *testing.T) {
+	content := generateContainer(t, "testdata/happy", scanner.Options{Expose: scanner.ExposeRootsOnly})
+
+	// Router is injected into Gateway, but its explicit binding keeps it exposed
+	for _, field := range []string{"SessionHandler *happy.SessionHandler", "SessionStore *happy.SessionStore", "Router *happy.Router", "Handler http.Handler"} {
+		if !strings.Contains(content, field) {
+			t.Errorf("expected exposed field '%s', got:\n%s", field, content)
+		}
+	}
+	if strings.Contains(content, "RedisClient *happy.RedisClient") {
+		t.Errorf("expected internal component to be no field, got:\n%s", content)
+	}
+}
+
+func TestGenerateOutput(t *testing.T) {
+	content := generateContainer(t, "testdata/happy", scanner.Options{})
+
+	// expected and unexpected hold regular expressions matched against the generated container
+	testcases := []struct {
+		name       string
+		expected   []string
+		unexpected []string
+	}{
+		{
+			// Wire fills the qualified fields, InitializeContainer and NewScope copy them into the exposed ones
+			name: "TestNamedComponents",
+			expected: []string{
+				`ReadDB\s+\*happy\.DB ` + "`wire:\"-\"`",
+				`SmsFactory\s+func\(\) \*happy\.SmsNotifier ` + "`wire:\"-\"`",
+				`c\.ReadDB = \(\*happy\.DB\)\(c\.qualified_ReadDB\)`,
+				`happy\.NewAuditLog\(p0, parent\.Mail\)`,
+				`s\.AuditLog = \(\*happy\.AuditLog\)\(s\.qualified_AuditLog\)`,
+			},
+			unexpected: []string{`(?m)^\t[A-Z]\w* +Qualified_`},
+		},
+		{
+			name: "TestLazy",
+			expected: []string{
+				`SearchIndex\s+\*flora\.Lazy\[\*happy\.SearchIndex\]`,
+				`func ProvideLazy_SearchIndex\(\) \(\*flora\.Lazy\[\*happy\.SearchIndex\], func\(\)\)`,
+				`return lazy, lazy\.Close`,
+			},
+		},
+		{
+			name: "TestRequestScope",
+			expected: []string{
+				`(?s)type FloraScope struct \{[^}]*Tx\s+\*happy\.Tx`,
+				`func \(c \*FloraContainer\) NewScope\(ctx context\.Context\) \(\*FloraScope, func\(\), error\)`,
+				`tx, cleanup, err := ProvideScoped_Tx\(ctx, parent\)`,
+				`happy\.NewUserRepo\(p0, parent\.LoudGreeter\)`,
+			},
+		},
+		{
+			name: "TestLifecycle",
+			expected: []string{
+				`if err := c\.EventConsumer\.Start\(ctx\); err != nil`,
+				`started = append\(started, c\.HttpServer\.Stop\)`,
+				`func \(c \*FloraContainer\) Stop\(ctx context\.Context\) error`,
+				`func floraStopAll\(ctx context\.Context, stops \[\]func\(context\.Context\) error\) error`,
+			},
+		},
+		{
+			name: "TestFieldInjection",
+			expected: []string{
+				`func Inject_Dashboard\(p0 happy\.Greeter, p1 happy\.SimpleLogger\) \*happy\.Dashboard`,
+				`(?s)return &happy\.Dashboard\{\s*Greeter: p0,\s*Logger:\s+p1,`,
+				`dashboard := Inject_Dashboard\(loudGreeter, simpleLogger\)`,
+			},
+		},
+		{
+			name: "TestDecorators",
+			expected: []string{
+				`LoudGreeter\s+\*happy\.LoudGreeter\n`,
+				`CacheClient\s+\*happy\.CacheClient\n`,
+				`loudGreeter := Provide_LoudGreeter\(qualified_decorated1_happy_Greeter_[0-9a-f]{8}\)`,
+			},
+			// Struct fields are the only tab-indented lines of a name followed by a type
+			unexpected: []string{`(?m)^\t\w*[dD]ecorated\w* [^:]`},
+		},
+		{
+			name: "TestModules",
+			expected: []string{
+				`(?s)type ApiContainer struct \{[^}]*ApiGateway\s+\*happy\.ApiGateway`,
+				`func InitializeApiContainer\(ctx context\.Context\) \(\*ApiContainer, func\(\), error\)`,
+				`(?s)type WorkerContainer struct \{[^}]*Worker\s+\*happy\.Worker`,
+			},
+			unexpected: []string{`(?s)type ApiContainer struct \{[^}]*HttpServer`},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, expected := range tc.expected {
+				if !regexp.MustCompile(expected).MatchString(content) {
+					t.Errorf("expected a match for '%s', got:\n%s", expected, content)
+				}
+			}
+			for _, unexpected := range tc.unexpected {
+				if match := regexp.MustCompile(unexpected).FindString(content); match != "" {
+					t.Errorf("expected no match for '%s', got '%s'", unexpected, match)
+				}
+			}
+		})
+	}
+}
+
+// generateContainer generates the container of the testdata directory and returns its source
+func generateContainer(t *testing.T, dir string, opts scanner.Options) string {
+	t.Helper()
+
+	packages, err := scanner.ScanPackages(dir, opts)
+	if err != nil {
+		t.Fatalf("ScanPackages failed: %v", err)
+	}
+	genCtx, err := scanner.ParsePackages(packages, opts)
+	if err != nil {
+		t.Fatalf("ParsePackages failed: %v", err)
+	}
+
+	tmpDir, err := os.MkdirTemp(".", "flora_test_out_*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := NewWireGenerator().Generate(tmpDir, genCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tmpDir, "flora_container.go"))
+	if err != nil {
+		t.Fatalf("expected container file: %v", err)
+	}
+	return string(content)
+}
+
+func TestContainerFileNameAndConstraint(t *testing.T) {
+	testcases := []struct {
+		name          string
+		genCtx        *engine.GeneratorContext
+		expFileName   string
+		expConstraint string
+	}{
+		{
+			name:          "TestDefault",
+			genCtx:        &engine.GeneratorContext{},
+			expFileName:   "flora_container.go",
+			expConstraint: "",
+		},
+		{
+			name:          "TestProfile",
+			genCtx:        &engine.GeneratorContext{Profile: "test"},
+			expFileName:   "flora_container.test.go",
+			expConstraint: "test",
+		},
+		{
+			name:          "TestTagsAndPlatform",
+			genCtx:        &engine.GeneratorContext{Profile: "prod", Tags: []string{"integration"}, GOOS: "linux", GOARCH: "amd64"},
+			expFileName:   "flora_container.prod.integration.linux.amd64.go",
+			expConstraint: "prod && integration && linux && amd64",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := containerFileName(tc.genCtx); got != tc.expFileName {
+				t.Errorf("expected file name %q, got %q", tc.expFileName, got)
+			}
+			if got := buildConstraint(tc.genCtx); got != tc.expConstraint {
+				t.Errorf("expected constraint %q, got %q", tc.expConstraint, got)
+			}
+		})
+	}
+}
+
+func TestExcludeOtherContainers(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"flora_container.go":            "//go:build !wireinject\n// +build !wireinject\n\npackage flora\n",
+		"flora_container.dev.go":        "//go:build !wireinject && (dev)\n\npackage flora\n",
+		"flora_container.prod.linux.go": "//go:build !wireinject && (prod && linux)\n\npackage flora\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Running it again, e.g. after regenerating a profile container, must not stack the terms
+	for range 2 {
+		if err := excludeOtherContainers(dir); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(dir, "flora_container.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	exp := "//go:build !wireinject && !dev && !(prod && linux)\n\npackage flora\n"
+	if string(content) != exp {
+		t.Errorf("expected the default container to exclude the others:\n%s\ngot:\n%s", exp, content)
+	}
+}
+
+func TestTypeArgRendering(t *testing.T) {
+	testcases := []struct {
+		name      string
+		typeArgs  []engine.TypeArgMetadata
+		pkgName   string
+		expList   string
+		expSuffix string
+	}{
+		{
+			name:      "TestNoTypeArgs",
+			pkgName:   "app",
+			expList:   "",
+			expSuffix: "",
+		},
+		{
+			name:      "TestLocalTypeArg",
+			typeArgs:  []engine.TypeArgMetadata{{Type: "app.User"}},
+			pkgName:   "app",
+			expList:   "[User]",
+			expSuffix: "_User",
+		},
+		{
+			name: "TestForeignAndNestedTypeArgs",
+			typeArgs: []engine.TypeArgMetadata{
+				{Type: "string"},
+				{Type: "*model.Page[app.User]", Imports: []string{"example.com/model", "example.com/app"}},
+			},
+			pkgName:   "app",
+			expList:   "[string, *model.Page[User]]",
+			expSuffix: "_string_PtrPageUser",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			importSet := make(map[string]bool)
+			if got := typeArgList(tc.typeArgs, tc.pkgName, importSet); got != tc.expList {
+				t.Errorf("expected type args %q, got %q", tc.expList, got)
+			}
+			if got := typeArgSuffix(tc.typeArgs); got != tc.expSuffix {
+				t.Errorf("expected suffix %q, got %q", tc.expSuffix, got)
+			}
+			for _, arg := range tc.typeArgs {
+				for _, imp := range arg.Imports {
+					if !importSet[imp] {
+						t.Errorf("expected import %q to be registered", imp)
+					}
+				}
+			}
+		})
+	}
+}
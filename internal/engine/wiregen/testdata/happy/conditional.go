@@ -0,0 +1,44 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happy
+
+import "github.com/soner3/flora"
+
+type Tracer interface {
+	Trace(msg string)
+}
+
+type TracingDefaults struct {
+	flora.Configuration
+}
+
+// flora:conditional=missing
+func (c *TracingDefaults) ProvideNoopTracer() Tracer {
+	return nil
+}
+
+type AppTracer struct {
+	flora.Component
+}
+
+func NewAppTracer() *AppTracer        { return nil }
+func (t *AppTracer) Trace(msg string) {}
+
+type TracedService struct {
+	flora.Component
+}
+
+func NewTracedService(t Tracer) *TracedService { return nil }
@@ -0,0 +1,38 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happy
+
+import "github.com/soner3/flora"
+
+type LoudGreeter struct {
+	flora.Component `flora:"decorates=Greeter,order=2"`
+	next            Greeter
+}
+
+func NewLoudGreeter(next Greeter) *LoudGreeter { return &LoudGreeter{next: next} }
+
+func (g *LoudGreeter) Greet() string { return g.next.Greet() + "!" }
+
+// flora:provider,decorates=Greeter,order=1
+func WithGreetingLog(next Greeter, l SimpleLogger) Greeter { return next }
+
+type CacheClient struct{}
+
+// flora:provider
+func NewCacheClient() *CacheClient { return &CacheClient{} }
+
+// flora:provider,decorates=CacheClient
+func WithRetry(next *CacheClient) *CacheClient { return next }
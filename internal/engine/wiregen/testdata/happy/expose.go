@@ -0,0 +1,36 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happy
+
+import "github.com/soner3/flora"
+
+type RedisClient struct {
+	flora.Component `flora:"internal"`
+}
+
+func NewRedisClient() *RedisClient { return nil }
+
+type SessionStore struct {
+	flora.Component `flora:"expose"`
+}
+
+func NewSessionStore(r *RedisClient) *SessionStore { return nil }
+
+type SessionHandler struct {
+	flora.Component
+}
+
+func NewSessionHandler(s *SessionStore) *SessionHandler { return nil }
@@ -0,0 +1,58 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happy
+
+import "github.com/soner3/flora"
+
+type User struct{ Name string }
+
+type Order struct{ ID int }
+
+type Store[T any] interface {
+	Save(v T)
+}
+
+type Handler[T any] interface {
+	Handle(v T)
+}
+
+type Repository[T any] struct {
+	flora.Component
+}
+
+func NewRepository[T any]() *Repository[T] { return nil }
+func (r *Repository[T]) Save(v T)          {}
+
+type Cache[K comparable, V any] struct {
+	flora.Component `flora:"scope=prototype"`
+}
+
+func NewCache[K comparable, V any]() *Cache[K, V] { return nil }
+
+type OrderHandler struct {
+	flora.Component
+}
+
+func NewOrderHandler() *OrderHandler   { return nil }
+func (h *OrderHandler) Handle(v Order) {}
+
+type AccountService struct {
+	flora.Component
+}
+
+func NewAccountService(users *Repository[User], orders Store[Order], cache func() *Cache[string, User], handlers []Handler[Order]) *AccountService {
+	return nil
+}
@@ -0,0 +1,35 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happy
+
+import (
+	"net/http"
+
+	"github.com/soner3/flora"
+)
+
+type Dashboard struct {
+	flora.Component
+	Greeter Greeter      `flora:"inject"`
+	Logger  SimpleLogger `flora:"inject"`
+	title   string
+}
+
+type Gateway struct {
+	flora.Component `flora:"autowire,scope=prototype"`
+	Router          *Router
+	Client          *http.Client `flora:"-"`
+}
@@ -0,0 +1,45 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happy
+
+import (
+	"context"
+
+	"github.com/soner3/flora"
+)
+
+type HttpServer struct {
+	flora.Component
+}
+
+func NewHttpServer(c *EventConsumer) *HttpServer      { return nil }
+func (s *HttpServer) Start(ctx context.Context) error { return nil }
+func (s *HttpServer) Stop(ctx context.Context) error  { return nil }
+
+type EventConsumer struct {
+	flora.Component
+}
+
+func NewEventConsumer(l SimpleLogger) *EventConsumer     { return nil }
+func (c *EventConsumer) Start(ctx context.Context) error { return nil }
+func (c *EventConsumer) Stop(ctx context.Context) error  { return nil }
+
+type MetricsFlusher struct {
+	flora.Component
+}
+
+func NewMetricsFlusher() MetricsFlusher                  { return MetricsFlusher{} }
+func (f *MetricsFlusher) Stop(ctx context.Context) error { return nil }
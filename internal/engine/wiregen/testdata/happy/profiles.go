@@ -0,0 +1,42 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happy
+
+import "github.com/soner3/flora"
+
+type Clock interface {
+	Now() int64
+}
+
+type FixedClock struct {
+	flora.Component `flora:"profile=dev|test"`
+}
+
+func NewFixedClock() *FixedClock { return nil }
+func (c *FixedClock) Now() int64 { return 0 }
+
+type SystemClock struct {
+	flora.Component `flora:"profile=!dev"`
+}
+
+func NewSystemClock() *SystemClock { return nil }
+func (c *SystemClock) Now() int64  { return 1 }
+
+type Scheduler struct {
+	flora.Component `flora:"profile=dev|prod"`
+}
+
+func NewScheduler(clock Clock) *Scheduler { return nil }
@@ -0,0 +1,36 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happy
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// flora:provider
+func NewSlogLogger() *slog.Logger { return slog.Default() }
+
+// flora:provider,scope=prototype
+func NewHTTPClient(l *slog.Logger) *http.Client {
+	return &http.Client{Timeout: 5 * time.Second}
+}
+
+// flora:provider,scope=lazy
+func ProvideTicker() (*time.Ticker, func()) {
+	t := time.NewTicker(time.Second)
+	return t, t.Stop
+}
@@ -0,0 +1,66 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happy
+
+import "github.com/soner3/flora"
+
+type DB struct {
+	dsn string
+}
+
+type DatabaseConfig struct {
+	flora.Configuration
+}
+
+// flora:name=readDB
+func (c *DatabaseConfig) ProvideReadDB() *DB {
+	return &DB{dsn: "read"}
+}
+
+// flora:name=writeDB
+func (c *DatabaseConfig) ProvideWriteDB() (*DB, func(), error) {
+	return &DB{dsn: "write"}, func() {}, nil
+}
+
+type UserStore struct {
+	flora.Component `flora:"qualifier=reader:readDB,qualifier=writer:writeDB"`
+}
+
+func NewUserStore(reader *DB, writer *DB) *UserStore { return nil }
+
+type Notifier interface {
+	Notify()
+}
+
+type MailNotifier struct {
+	flora.Component `flora:"name=mail"`
+}
+
+func NewMailNotifier() *MailNotifier { return nil }
+func (n *MailNotifier) Notify()      {}
+
+type SmsNotifier struct {
+	flora.Component `flora:"name=sms,scope=prototype"`
+}
+
+func NewSmsNotifier() *SmsNotifier { return nil }
+func (n *SmsNotifier) Notify()     {}
+
+type Alerting struct {
+	flora.Component `flora:"qualifier=primary:mail,qualifier=fallback:sms"`
+}
+
+func NewAlerting(primary Notifier, fallback func() *SmsNotifier) *Alerting { return nil }
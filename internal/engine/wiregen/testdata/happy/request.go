@@ -0,0 +1,51 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happy
+
+import (
+	"context"
+
+	"github.com/soner3/flora"
+)
+
+type UnitOfWork interface {
+	Commit() error
+}
+
+type Tx struct {
+	flora.Component `flora:"scope=request"`
+}
+
+func NewTx(ctx context.Context, l SimpleLogger) (*Tx, func(), error) { return nil, func() {}, nil }
+func (t *Tx) Commit() error                                          { return nil }
+
+type UserRepo struct {
+	flora.Component `flora:"scope=request"`
+}
+
+func NewUserRepo(tx *Tx, g Greeter) *UserRepo { return nil }
+
+type OrderRepo struct {
+	flora.Component `flora:"scope=request"`
+}
+
+func NewOrderRepo(uow UnitOfWork, plugins []Plugin, makeIface func() Iface) *OrderRepo { return nil }
+
+type AuditLog struct {
+	flora.Component `flora:"scope=request,name=auditLog,qualifier=notifier:mail"`
+}
+
+func NewAuditLog(tx *Tx, notifier *MailNotifier) *AuditLog { return nil }
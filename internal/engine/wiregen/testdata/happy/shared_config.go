@@ -0,0 +1,50 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happy
+
+import "github.com/soner3/flora"
+
+type Settings struct {
+	URL string
+}
+
+type Mailer struct{}
+
+type MailTemplate struct{}
+
+type Outbox struct{}
+
+type Session struct{}
+
+type MailConfig struct {
+	flora.Configuration
+	settings *Settings
+}
+
+func NewMailConfig(l SimpleLogger) *MailConfig {
+	return &MailConfig{settings: &Settings{URL: "smtp://localhost"}}
+}
+
+func (c *MailConfig) ProvideMailer() *Mailer { return &Mailer{} }
+
+// flora:scope=prototype
+func (c *MailConfig) ProvideMailTemplate() *MailTemplate { return &MailTemplate{} }
+
+// flora:scope=lazy
+func (c *MailConfig) ProvideOutbox(m *Mailer) (*Outbox, error) { return &Outbox{}, nil }
+
+// flora:scope=request
+func (c *MailConfig) ProvideSession(m *Mailer) *Session { return &Session{} }
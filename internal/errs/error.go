@@ -17,6 +17,7 @@ package errs
 
 import (
 	"fmt"
+	"go/token"
 	"hash/fnv"
 	"runtime/debug"
 	"time"
@@ -29,6 +30,8 @@ type FloraError struct {
 	StackTrace string
 	CreatedAt  time.Time
 	Misc       map[string]any
+	// Position is the source position the error refers to, if any
+	Position token.Position
 }
 
 func Wrap(err error, message string, args ...any) *FloraError {
@@ -45,6 +48,7 @@ func Wrap(err error, message string, args ...any) *FloraError {
 			CreatedAt:  time.Now(),
 			ID:         e.ID,
 			Misc:       nil,
+			Position:   e.Position,
 		}
 	}
 
@@ -60,7 +64,26 @@ func Wrap(err error, message string, args ...any) *FloraError {
 	}
 }
 
+// WrapAt wraps the error like Wrap and records the source position it refers to
+func WrapAt(pos token.Position, err error, message string, args ...any) *FloraError {
+	e := Wrap(err, message, args...)
+	e.Position = pos
+	return e
+}
+
+// Error returns the message in 'file:line:col: message' form if the error has a position
 func (e *FloraError) Error() string {
+	if e.Position.IsValid() {
+		return fmt.Sprintf("%s: %s", e.Position, e.text())
+	}
+	return e.text()
+}
+
+// text returns the message chain without the position, which is printed only once
+func (e *FloraError) text() string {
+	if inner, ok := e.Inner.(*FloraError); ok && inner.Position == e.Position {
+		return fmt.Sprintf("%s: %s", e.Message, inner.text())
+	}
 	if e.Inner != nil {
 		return fmt.Sprintf("%s: %v", e.Message, e.Inner)
 	}
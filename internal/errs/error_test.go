@@ -18,6 +18,7 @@ package errs
 import (
 	"errors"
 	"fmt"
+	"go/token"
 	"testing"
 )
 
@@ -64,3 +65,38 @@ func TestError(t *testing.T) {
 		t.Errorf("expected: %s, got: %s", fmt.Sprintf("%s: %s", msg, inner.Message), wErr.Error())
 	}
 }
+
+func TestErrorWithPosition(t *testing.T) {
+	pos := token.Position{Filename: "service.go", Line: 12, Column: 6}
+	inner := WrapAt(pos, nil, "inner")
+
+	testcases := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{
+			name:     "TestWrapAt",
+			err:      inner,
+			expected: "service.go:12:6: inner",
+		},
+		{
+			name:     "TestWrapInheritsPosition",
+			err:      Wrap(inner, "outer"),
+			expected: "service.go:12:6: outer: inner",
+		},
+		{
+			name:     "TestWrapAtOtherPosition",
+			err:      WrapAt(token.Position{Filename: "main.go", Line: 3, Column: 1}, inner, "outer"),
+			expected: "main.go:3:1: outer: service.go:12:6: inner",
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if tc.err.Error() != tc.expected {
+				t.Errorf("expected: %s, got: %s", tc.expected, tc.err.Error())
+			}
+		})
+	}
+}
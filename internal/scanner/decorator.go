@@ -0,0 +1,207 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package scanner
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/soner3/flora/internal/engine"
+	"github.com/soner3/flora/internal/errs"
+)
+
+// resolveDecorates returns the type a decorator wraps. Interfaces are decorated
+// as they are, other types in the form the decorator returns them.
+func resolveDecorates(compInfo *componentInfo, metadata *engine.ComponentMetadata, sig *types.Signature) (types.Type, error) {
+	if metadata.Decorates == "" {
+		return nil, nil
+	}
+
+	if metadata.Qualifier != "" || metadata.Scope != ScopeSingleton {
+		chainErr := fmt.Errorf("%w: %s", ErrInvalidDecorator, metadata.StructName)
+		return nil, errs.WrapAt(metadata.Position, chainErr, "decorator '%s' in package '%s' must be an unnamed singleton", metadata.ConstructorName, metadata.PackageName)
+	}
+
+	named, ok := lookupType(compInfo, metadata.Decorates).(*types.Named)
+	if !ok || named.TypeParams().Len() > 0 {
+		chainErr := fmt.Errorf("%w: %s", ErrInvalidDecorator, metadata.Decorates)
+		return nil, errs.WrapAt(metadata.Position, chainErr, "'%s' decorated by '%s' is not a non-generic type visible in package '%s'",
+			metadata.Decorates, metadata.ConstructorName, compInfo.Pkg.Name)
+	}
+
+	retType := sig.Results().At(0).Type()
+	decorated := retType
+	if _, isInterface := named.Underlying().(*types.Interface); isInterface {
+		if !types.AssignableTo(retType, named) {
+			chainErr := fmt.Errorf("%w: %s", ErrInvalidDecorator, metadata.Decorates)
+			return nil, errs.WrapAt(metadata.Position, chainErr, "decorator '%s' in package '%s' returns '%s', which does not implement '%s'",
+				metadata.ConstructorName, metadata.PackageName, retType.String(), metadata.Decorates)
+		}
+		decorated = named
+	} else if ptr, isPtr := retType.(*types.Pointer); !types.Identical(retType, named) && (!isPtr || !types.Identical(ptr.Elem(), named)) {
+		chainErr := fmt.Errorf("%w: %s", ErrInvalidDecorator, metadata.Decorates)
+		return nil, errs.WrapAt(metadata.Position, chainErr, "decorator '%s' in package '%s' returns '%s', but must return '%s' or '*%s'",
+			metadata.ConstructorName, metadata.PackageName, retType.String(), metadata.Decorates, metadata.Decorates)
+	}
+
+	var next []*types.Var
+	for v := range sig.Params().Variables() {
+		if types.Identical(v.Type(), decorated) {
+			next = append(next, v)
+		}
+	}
+	if len(next) != 1 || next[0].Name() == "" || next[0].Name() == "_" {
+		chainErr := fmt.Errorf("%w: %s", ErrInvalidDecorator, metadata.Decorates)
+		return nil, errs.WrapAt(metadata.Position, chainErr, "decorator '%s' in package '%s' must take the decorated '%s' as exactly one named parameter",
+			metadata.ConstructorName, metadata.PackageName, decorated.String())
+	}
+
+	return decorated, nil
+}
+
+// applyDecorators chains the decorators of every decorated type ordered by 'order='.
+// The decorated provider and all but the last decorator are named internally, so every
+// decorator receives the previous link of the chain and consumers only see the last one.
+// The internal names contain the package path and a ':', so they never clash with
+// each other or with a 'name=' of the user. The errors of all chains are joined.
+func applyDecorators(components []*scannedComponent) error {
+	var decoratedTypes []types.Type
+	chains := make(map[string][]*scannedComponent)
+	for _, comp := range components {
+		if comp.Decorates == nil {
+			continue
+		}
+		key := comp.Decorates.String()
+		if _, exists := chains[key]; !exists {
+			decoratedTypes = append(decoratedTypes, comp.Decorates)
+		}
+		chains[key] = append(chains[key], comp)
+	}
+
+	var errList []error
+	for _, decorated := range decoratedTypes {
+		decorators := chains[decorated.String()]
+		slices.SortStableFunc(decorators, func(a, b *scannedComponent) int {
+			return cmp.Compare(a.Metadata.Order, b.Metadata.Order)
+		})
+
+		original, err := decoratedProvider(components, decorated, decorators[0].Metadata.Position)
+		if err != nil {
+			errList = append(errList, err)
+			continue
+		}
+
+		typeName := decorated
+		if ptr, isPtr := typeName.(*types.Pointer); isPtr {
+			typeName = ptr.Elem()
+		}
+		obj := typeName.(*types.Named).Obj()
+		name := obj.Pkg().Path() + "." + obj.Name()
+
+		// The links of the chain are only injected into the next decorator
+		prev := decoratorQualifier(name, 0)
+		original.Metadata.Qualifier = prev
+		original.Metadata.Hidden = true
+
+		for i, dec := range decorators {
+			for j := range dec.Signature.Params().Len() {
+				v := dec.Signature.Params().At(j)
+				if !types.Identical(v.Type(), decorated) {
+					continue
+				}
+				if dec.Metadata.ParamQualifiers == nil {
+					dec.Metadata.ParamQualifiers = make(map[string]string)
+				}
+				dec.Metadata.ParamQualifiers[v.Name()] = prev
+				dec.Metadata.Params[j].Qualifier = prev
+			}
+			delete(dec.NeededInterfaces, decorated.String())
+
+			if i < len(decorators)-1 {
+				prev = decoratorQualifier(name, i+1)
+				dec.Metadata.Qualifier = prev
+				dec.Metadata.Hidden = true
+			}
+			log.Debug("Chained decorator", "type", decorated.String(), "decorator", dec.Metadata.ConstructorName, "wraps", original.Metadata.StructName)
+		}
+	}
+
+	return errors.Join(errList...)
+}
+
+// decoratorQualifier names link n of the decorator chain of a type, 0 being the undecorated provider
+func decoratorQualifier(name string, n int) string {
+	if n == 0 {
+		return "undecorated:" + name
+	}
+	return fmt.Sprintf("decorated%d:%s", n, name)
+}
+
+// DecoratorLink reports whether the qualifier names a link of a decorator chain.
+// It returns the decorated type and the link, 0 being the undecorated provider.
+func DecoratorLink(qualifier string) (string, int, bool) {
+	prefix, name, found := strings.Cut(qualifier, ":")
+	if !found {
+		return "", 0, false
+	}
+	if prefix == "undecorated" {
+		return name, 0, true
+	}
+	digits, isLink := strings.CutPrefix(prefix, "decorated")
+	n, err := strconv.Atoi(digits)
+	if !isLink || err != nil || n < 1 {
+		return "", 0, false
+	}
+	return name, n, true
+}
+
+// decoratedProvider returns the component the first decorator of a type, declared at pos, wraps.
+// A provider of the exact type wins over components implementing an interface.
+func decoratedProvider(components []*scannedComponent, decorated types.Type, pos token.Position) (*scannedComponent, error) {
+	var direct, implementers []*scannedComponent
+	for _, comp := range components {
+		if comp.Decorates != nil || comp.Metadata.Qualifier != "" || comp.Metadata.Scope != ScopeSingleton {
+			continue
+		}
+		if types.Identical(comp.Signature.Results().At(0).Type(), decorated) {
+			direct = append(direct, comp)
+		} else if types.IsInterface(decorated) && implementsIface(comp, decorated) {
+			implementers = append(implementers, comp)
+		}
+	}
+
+	candidates := direct
+	if len(candidates) == 0 {
+		candidates = implementers
+	}
+	if len(candidates) > 1 {
+		candidates = slices.DeleteFunc(candidates, func(comp *scannedComponent) bool { return !comp.Metadata.IsPrimary })
+	}
+
+	if len(candidates) != 1 {
+		chainErr := fmt.Errorf("%w: %s", ErrInvalidDecorator, decorated.String())
+		return nil, errs.WrapAt(pos, chainErr, "cannot decorate '%s': expected exactly one unnamed singleton (or one marked 'primary') to provide it, found %d",
+			decorated.String(), len(candidates))
+	}
+
+	return candidates[0], nil
+}
@@ -0,0 +1,167 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package scanner
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/soner3/flora/internal/engine"
+	"github.com/soner3/flora/internal/errs"
+	"golang.org/x/tools/go/packages"
+)
+
+// directivePrefixes are the accepted forms of a magic comment, the Go
+// directive form '//flora:' and the spaced form '// flora:'
+var directivePrefixes = []string{"//flora:", "// flora:"}
+
+// directiveText returns the settings of a single magic comment line
+func directiveText(comment *ast.Comment) (string, bool) {
+	for _, prefix := range directivePrefixes {
+		if after, ok := strings.CutPrefix(strings.TrimSpace(comment.Text), prefix); ok {
+			return strings.TrimSpace(after), true
+		}
+	}
+	return "", false
+}
+
+// floraDirective returns the magic comments of a doc comment merged into one
+// comma-separated list, together with the position of the first one
+func floraDirective(doc *ast.CommentGroup) (string, token.Pos) {
+	if doc == nil {
+		return "", token.NoPos
+	}
+
+	var parts []string
+	pos := token.NoPos
+	for _, comment := range doc.List {
+		text, ok := directiveText(comment)
+		if !ok {
+			continue
+		}
+		if !pos.IsValid() {
+			pos = comment.Pos()
+		}
+		if text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, ","), pos
+}
+
+// cutProviderDirective removes 'provider' from a directive and reports whether it was present
+func cutProviderDirective(directive string) (string, bool) {
+	var rest []string
+	found := false
+	for part := range strings.SplitSeq(directive, ",") {
+		part = strings.TrimSpace(part)
+		if part == ProviderDirective {
+			found = true
+		} else if part != "" {
+			rest = append(rest, part)
+		}
+	}
+	return strings.Join(rest, ","), found
+}
+
+// ignoredDirectives reports the magic comments flora never reads: those not in
+// the doc comment of a provider func, a component constructor or an exported
+// Configuration method. Their unknown options are reported as well, as a
+// misspelled 'provider' is the most common reason a directive is ignored.
+func ignoredDirectives(pkgs []*packages.Package, compInfos []componentInfo) []error {
+	var warnings []error
+
+	for _, pkg := range pkgs {
+		readFuncs := make(map[string]bool)
+		configs := make(map[string]bool)
+		for _, compInfo := range compInfos {
+			if compInfo.Pkg != pkg {
+				continue
+			}
+			if compInfo.Marker == ProviderDirective {
+				readFuncs[compInfo.Name] = true
+				continue
+			}
+			if compInfo.Marker == ConfigurationMarker {
+				configs[compInfo.Name] = true
+			}
+			// Invalid tags are reported when the component is processed
+			metadata := &engine.ComponentMetadata{StructName: compInfo.Name}
+			_ = parseFloraTag(compInfo.Tag, token.Position{}, metadata)
+			readFuncs[metadata.ConstructorName] = true
+		}
+
+		for _, file := range pkg.Syntax {
+			read := make(map[*ast.CommentGroup]bool)
+			for _, decl := range file.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Doc == nil {
+					continue
+				}
+				if funcDecl.Recv == nil {
+					read[funcDecl.Doc] = readFuncs[funcDecl.Name.Name]
+				} else {
+					read[funcDecl.Doc] = ast.IsExported(funcDecl.Name.Name) && configs[receiverName(funcDecl)]
+				}
+			}
+
+			for _, group := range file.Comments {
+				if read[group] {
+					continue
+				}
+				directive, pos := floraDirective(group)
+				if !pos.IsValid() {
+					continue
+				}
+				position := pkg.Fset.Position(pos)
+				warnings = append(warnings, errs.WrapAt(position, ErrIgnoredDirective,
+					"magic comment '%s' is ignored: only provider funcs, component constructors and exported Configuration methods are read", directive))
+
+				options := append(slices.Sorted(maps.Keys(tagOptions)), ProviderDirective)
+				for part := range strings.SplitSeq(directive, ",") {
+					key, _, _ := strings.Cut(strings.TrimSpace(part), "=")
+					if key == "" || slices.Contains(options, key) {
+						continue
+					}
+					chainErr := fmt.Errorf("%w: %s", ErrUnknownOption, key)
+					warnings = append(warnings, errs.WrapAt(position, chainErr, "unknown flora option '%s' in magic comment%s", key, didYouMean(key, options)))
+				}
+			}
+		}
+	}
+
+	return warnings
+}
+
+// receiverName returns the name of the type a method is declared on
+func receiverName(funcDecl *ast.FuncDecl) string {
+	if funcDecl.Recv == nil || len(funcDecl.Recv.List) == 0 {
+		return ""
+	}
+	switch t := funcDecl.Recv.List[0].Type.(type) {
+	case *ast.Ident:
+		return t.Name
+	case *ast.StarExpr:
+		if ident, ok := t.X.(*ast.Ident); ok {
+			return ident.Name
+		}
+	}
+	return ""
+}
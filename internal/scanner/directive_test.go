@@ -0,0 +1,54 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/soner3/flora/internal/errs"
+)
+
+func TestIgnoredDirectives(t *testing.T) {
+	packages, err := ScanPackages("testdata/happy_directive_forms", Options{})
+	if err != nil {
+		t.Fatalf("ScanPackages failed: %v", err)
+	}
+
+	var got []string
+	for _, warning := range ignoredDirectives(packages, *parseMarkedComponents(packages)) {
+		var floraErr *errs.FloraError
+		if !errors.As(warning, &floraErr) {
+			t.Fatalf("expected a FloraError, got %v", warning)
+		}
+		kind := "ignored"
+		if errors.Is(warning, ErrUnknownOption) {
+			kind = "unknown"
+			if !strings.Contains(warning.Error(), "did you mean 'provider'?") {
+				t.Errorf("expected a suggestion for the misspelled provider, got %v", warning)
+			}
+		}
+		got = append(got, fmt.Sprintf("%s %d", kind, floraErr.Position.Line))
+	}
+
+	expected := []string{"ignored 62", "unknown 62", "ignored 67"}
+	if !slices.Equal(got, expected) {
+		t.Errorf("expected warnings %v, got %v", expected, got)
+	}
+}
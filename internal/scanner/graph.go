@@ -0,0 +1,406 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"go/types"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/soner3/flora/internal/engine"
+	"github.com/soner3/flora/internal/errs"
+)
+
+// resolveDependencies records for every parameter which components satisfy
+// it, following the same rules as the generated wire graph. Parameters that
+// cannot be resolved are left untouched, wire reports them.
+func resolveDependencies(components []*scannedComponent, fset *token.FileSet) error {
+	named := make(map[string]*scannedComponent)
+	for _, comp := range components {
+		if comp.Metadata.Qualifier != "" {
+			named[comp.Metadata.Qualifier] = comp
+		}
+	}
+
+	var scopeErrs []error
+	for _, comp := range components {
+		for i := range comp.Signature.Params().Len() {
+			v := comp.Signature.Params().At(i)
+			param := &comp.Metadata.Params[i]
+
+			source, providers, iface := resolveParam(v.Type(), comp.Metadata.ParamQualifiers[v.Name()], components, named)
+			param.Source = source
+			param.Interface = iface
+			param.Providers = nil
+			for _, provider := range providers {
+				param.Providers = append(param.Providers, provider.Metadata)
+			}
+
+			if comp.Metadata.Scope == ScopeRequest {
+				continue
+			}
+			for _, provider := range providers {
+				if provider.Metadata.Scope == ScopeRequest {
+					chainErr := fmt.Errorf("%w: %s", ErrScopeMismatch, provider.Metadata.StructName)
+					scopeErrs = append(scopeErrs, errs.WrapAt(paramPosition(comp, v, fset), chainErr, "%s component '%s' in package '%s' cannot depend on request-scoped component '%s' in package '%s'",
+						comp.Metadata.Scope, comp.Metadata.StructName, comp.Metadata.PackageName, provider.Metadata.StructName, provider.Metadata.PackageName))
+				}
+			}
+		}
+	}
+
+	return errors.Join(scopeErrs...)
+}
+
+// detectCycles reports the first dependency cycle with every edge on its path.
+// Edges follow the resolved parameters, so bound interfaces, slices and maps
+// take part like plain dependencies.
+func detectCycles(components []*scannedComponent, fset *token.FileSet) error {
+	byMetadata := make(map[*engine.ComponentMetadata]*scannedComponent)
+	for _, comp := range components {
+		byMetadata[comp.Metadata] = comp
+	}
+
+	type edge struct {
+		from  *scannedComponent
+		param int
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[*scannedComponent]int)
+	var path []edge
+
+	var visit func(comp *scannedComponent) []edge
+	visit = func(comp *scannedComponent) []edge {
+		state[comp] = visiting
+		for i, param := range comp.Metadata.Params {
+			for _, provider := range param.Providers {
+				next := byMetadata[provider]
+				path = append(path, edge{from: comp, param: i})
+				switch state[next] {
+				case visiting:
+					start := slices.IndexFunc(path, func(e edge) bool { return e.from == next })
+					return path[start:]
+				case 0:
+					if cycle := visit(next); cycle != nil {
+						return cycle
+					}
+				}
+				path = path[:len(path)-1]
+			}
+		}
+		state[comp] = done
+		return nil
+	}
+
+	for _, comp := range components {
+		if state[comp] != 0 {
+			continue
+		}
+		cycle := visit(comp)
+		if cycle == nil {
+			continue
+		}
+
+		names := []string{cycle[0].from.Metadata.StructName}
+		var lines []string
+		for j, e := range cycle {
+			to := cycle[(j+1)%len(cycle)].from
+			names = append(names, to.Metadata.StructName)
+
+			v := e.from.Signature.Params().At(e.param)
+			lines = append(lines, fmt.Sprintf("%s: %s requires %s (%s) through parameter '%s'",
+				paramPosition(e.from, v, fset), constructorLabel(e.from.Metadata), to.Metadata.StructName, constructorLabel(to.Metadata), v.Name()))
+		}
+
+		chainErr := fmt.Errorf("%w: %s", ErrDependencyCycle, strings.Join(names, " -> "))
+		return errs.Wrap(chainErr, "components depend on each other in a cycle:\n\t%s", strings.Join(lines, "\n\t"))
+	}
+
+	return nil
+}
+
+// paramPosition returns the position of a constructor parameter. Parameters the
+// generator adds, like the receiver of a Configuration method, have none, so
+// the component itself is used instead.
+func paramPosition(comp *scannedComponent, v *types.Var, fset *token.FileSet) token.Position {
+	if v.Pos().IsValid() {
+		return fset.Position(v.Pos())
+	}
+	return comp.Metadata.Position
+}
+
+// requestPosition returns the position of the first constructor parameter whose
+// type matches, so binding errors point at a place that needs the binding
+func requestPosition(components []*scannedComponent, fset *token.FileSet, matches func(types.Type) bool) token.Position {
+	for _, comp := range components {
+		for v := range comp.Signature.Params().Variables() {
+			if matches(v.Type()) {
+				return paramPosition(comp, v, fset)
+			}
+		}
+	}
+	return token.Position{}
+}
+
+// constructorLabel names the function that provides a component
+func constructorLabel(meta *engine.ComponentMetadata) string {
+	if meta.ConfigMethodName != "" {
+		return meta.ConfigStructName + "." + meta.ConfigMethodName
+	}
+	return meta.ConstructorName
+}
+
+// resolveParam returns the source and the providers of a parameter type
+func resolveParam(paramType types.Type, qualifier string, components []*scannedComponent, named map[string]*scannedComponent) (string, []*scannedComponent, *engine.InterfaceMetadata) {
+	if qualifier != "" {
+		if target, ok := named[qualifier]; ok {
+			return engine.SourceComponent, []*scannedComponent{target}, nil
+		}
+		return "", nil, nil
+	}
+
+	if isContext(paramType) {
+		return engine.SourceContext, nil, nil
+	}
+
+	unqualified := func(match func(comp *scannedComponent) bool) []*scannedComponent {
+		var result []*scannedComponent
+		for _, comp := range components {
+			if comp.Metadata.Qualifier == "" && match(comp) {
+				result = append(result, comp)
+			}
+		}
+		return result
+	}
+
+	switch t := paramType.(type) {
+	case *types.Slice:
+		if iface, ok := namedInterface(t.Elem()); ok {
+			return engine.SourceSlice, collected(components, iface), interfaceMetadata(iface)
+		}
+	case *types.Map:
+		if iface, ok := namedInterface(t.Elem()); ok {
+			return engine.SourceMap, collected(components, iface), interfaceMetadata(iface)
+		}
+	case *types.Signature:
+		if t.Params().Len() > 0 || t.Results().Len() == 0 {
+			return "", nil, nil
+		}
+		retType := t.Results().At(0).Type()
+		if prototypes := unqualified(func(comp *scannedComponent) bool {
+			return comp.Metadata.Scope == ScopePrototype && types.Identical(comp.Signature.Results().At(0).Type(), retType)
+		}); len(prototypes) > 0 {
+			return engine.SourceComponent, prototypes[:1], nil
+		}
+		if iface, ok := namedInterface(retType); ok {
+			if prototypes := unqualified(func(comp *scannedComponent) bool {
+				return comp.Metadata.Scope == ScopePrototype && implementsBound(comp, iface)
+			}); len(prototypes) > 0 {
+				return engine.SourceFactory, prototypes[:1], interfaceMetadata(iface)
+			}
+		}
+		return "", nil, nil
+	}
+
+	if lazies := unqualified(func(comp *scannedComponent) bool {
+		return comp.Metadata.Scope == ScopeLazy && types.Identical(comp.LazyType, paramType)
+	}); len(lazies) > 0 {
+		return engine.SourceComponent, lazies[:1], nil
+	}
+
+	values := unqualified(func(comp *scannedComponent) bool {
+		return isValueScope(comp) && types.Identical(comp.Signature.Results().At(0).Type(), paramType)
+	})
+	if len(values) > 0 {
+		return engine.SourceComponent, values[:1], nil
+	}
+
+	if iface, ok := namedInterface(paramType); ok {
+		if bound := unqualified(func(comp *scannedComponent) bool {
+			return isValueScope(comp) && implementsBound(comp, iface)
+		}); len(bound) > 0 {
+			return engine.SourceComponent, bound[:1], interfaceMetadata(iface)
+		}
+	}
+
+	return "", nil, nil
+}
+
+// isValueScope reports whether a component is injected as its plain value
+func isValueScope(comp *scannedComponent) bool {
+	return comp.Metadata.Scope == ScopeSingleton || comp.Metadata.Scope == ScopeRequest
+}
+
+// collected returns the components that are injected into a slice or map of the interface
+func collected(components []*scannedComponent, iface *types.Named) []*scannedComponent {
+	var result []*scannedComponent
+	for _, comp := range components {
+		if isCollectable(comp) && implementsIface(comp, iface) {
+			result = append(result, comp)
+		}
+	}
+	return result
+}
+
+// isCollectable reports whether a component takes part in slice and map bindings
+func isCollectable(comp *scannedComponent) bool {
+	return comp.Metadata.Scope != ScopeLazy && comp.Metadata.Scope != ScopeRequest && !comp.Shared
+}
+
+// implementsBound reports whether the interface was bound to the component
+func implementsBound(comp *scannedComponent, iface *types.Named) bool {
+	meta := interfaceMetadata(iface)
+	return slices.ContainsFunc(comp.Metadata.Implements, func(impl engine.InterfaceMetadata) bool {
+		return impl.PackagePath == meta.PackagePath && impl.InterfaceName == meta.InterfaceName &&
+			slices.EqualFunc(impl.TypeArgs, meta.TypeArgs, func(a, b engine.TypeArgMetadata) bool { return a.Type == b.Type })
+	})
+}
+
+// namedInterface returns the type as a named, non-empty interface
+func namedInterface(t types.Type) (*types.Named, bool) {
+	named, ok := t.(*types.Named)
+	if !ok {
+		return nil, false
+	}
+	iface, ok := named.Underlying().(*types.Interface)
+	return named, ok && !iface.Empty()
+}
+
+// interfaceMetadata describes a named interface
+func interfaceMetadata(named *types.Named) *engine.InterfaceMetadata {
+	return &engine.InterfaceMetadata{
+		PackageName:   named.Obj().Pkg().Name(),
+		PackagePath:   named.Obj().Pkg().Path(),
+		InterfaceName: named.Obj().Name(),
+		TypeArgs:      typeArgsMetadata(named.TypeArgs()),
+	}
+}
+
+// isContext reports whether the type is context.Context
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+// lifecycleOrder returns the singletons implementing flora.Starter or
+// flora.Stopper so that every component comes after its dependencies
+func lifecycleOrder(components []*engine.ComponentMetadata) []*engine.ComponentMetadata {
+	var order []*engine.ComponentMetadata
+	visited := make(map[*engine.ComponentMetadata]bool)
+
+	var visit func(comp *engine.ComponentMetadata)
+	visit = func(comp *engine.ComponentMetadata) {
+		if visited[comp] {
+			return
+		}
+		visited[comp] = true
+
+		for _, param := range comp.Params {
+			for _, provider := range param.Providers {
+				visit(provider)
+			}
+		}
+
+		if comp.IsStarter || comp.IsStopper {
+			order = append(order, comp)
+		}
+	}
+
+	for _, comp := range components {
+		visit(comp)
+	}
+
+	return order
+}
+
+// moduleClosures returns every module with its roots, the components tagged
+// 'module=', and everything they transitively depend on
+func moduleClosures(components []*engine.ComponentMetadata) ([]*engine.ModuleMetadata, error) {
+	roots := make(map[string][]*engine.ComponentMetadata)
+	for _, comp := range components {
+		for _, module := range comp.Modules {
+			if comp.Scope == ScopeRequest {
+				return nil, errs.WrapAt(comp.Position, ErrInvalidMetadata, "request-scoped component '%s' in package '%s' cannot be the root of module '%s'",
+					comp.StructName, comp.PackageName, module)
+			}
+			roots[module] = append(roots[module], comp)
+		}
+	}
+
+	var modules []*engine.ModuleMetadata
+	for _, name := range slices.Sorted(maps.Keys(roots)) {
+		included := make(map[*engine.ComponentMetadata]bool)
+		queue := slices.Clone(roots[name])
+		for len(queue) > 0 {
+			comp := queue[0]
+			queue = queue[1:]
+			if included[comp] {
+				continue
+			}
+			included[comp] = true
+			for _, param := range comp.Params {
+				queue = append(queue, param.Providers...)
+			}
+		}
+
+		module := &engine.ModuleMetadata{Name: name}
+		for _, comp := range components {
+			if included[comp] {
+				module.Components = append(module.Components, comp)
+			}
+		}
+		modules = append(modules, module)
+	}
+
+	return modules, nil
+}
+
+// hideComponents marks the components that are not exposed on the container:
+// those tagged 'internal', the inner links of decorator chains and, with
+// roots-only, those another component depends on unless they are tagged
+// 'expose', bind an interface explicitly or are the root of a module
+func hideComponents(components []*engine.ComponentMetadata, mode string) error {
+	dependedOn := make(map[*engine.ComponentMetadata]bool)
+	for _, comp := range components {
+		for _, param := range comp.Params {
+			for _, provider := range param.Providers {
+				dependedOn[provider] = true
+			}
+		}
+	}
+
+	for _, comp := range components {
+		if comp.Expose && comp.Internal {
+			return errs.WrapAt(comp.Position, ErrInvalidMetadata, "component '%s' in package '%s' cannot be both exposed and internal",
+				comp.StructName, comp.PackageName)
+		}
+		if comp.Scope == ScopeRequest {
+			continue
+		}
+		exposed := mode != ExposeRootsOnly || comp.Expose || len(comp.Binds) > 0 || len(comp.Modules) > 0 || !dependedOn[comp]
+		comp.Hidden = comp.Hidden || comp.Internal || !exposed
+	}
+
+	return nil
+}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package scanner
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/soner3/flora/internal/engine"
+)
+
+func TestResolveDependencies(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_request", Options{})
+
+	params := make(map[string][]engine.ParamMetadata)
+	for _, comp := range genCtx.Components {
+		params[comp.StructName] = comp.Params
+	}
+
+	testcases := []struct {
+		name        string
+		component   string
+		index       int
+		expSource   string
+		expProvider string
+	}{
+		{name: "TestContext", component: "Tx", index: 0, expSource: engine.SourceContext},
+		{name: "TestSingleton", component: "Tx", index: 1, expSource: engine.SourceComponent, expProvider: "Pool"},
+		{name: "TestBoundInterface", component: "OrderRepo", index: 0, expSource: engine.SourceComponent, expProvider: "Tx"},
+		{name: "TestSlice", component: "OrderRepo", index: 1, expSource: engine.SourceSlice, expProvider: "AuditPlugin"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			param := params[tc.component][tc.index]
+			if param.Source != tc.expSource {
+				t.Errorf("expected source %q, got %q", tc.expSource, param.Source)
+			}
+			if tc.expProvider == "" {
+				if len(param.Providers) != 0 {
+					t.Errorf("expected no providers, got %d", len(param.Providers))
+				}
+				return
+			}
+			if len(param.Providers) != 1 || param.Providers[0].StructName != tc.expProvider {
+				t.Errorf("expected provider %s, got %v", tc.expProvider, param.Providers)
+			}
+		})
+	}
+}
+
+func TestLifecycleOrder(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_lifecycle", Options{})
+
+	var order []string
+	for _, comp := range genCtx.Lifecycle {
+		order = append(order, comp.StructName)
+	}
+
+	if !slices.Equal(order, []string{"KafkaBroker", "Consumer", "Server"}) {
+		t.Errorf("expected lifecycle order [KafkaBroker Consumer Server], got %v", order)
+	}
+}
+
+func TestDetectCycles(t *testing.T) {
+	packages, err := ScanPackages("testdata/err_cycle", Options{})
+	if err != nil {
+		t.Fatalf("ScanPackages failed: %v", err)
+	}
+
+	_, err = ParsePackages(packages, Options{})
+	if !errors.Is(err, ErrDependencyCycle) {
+		t.Fatalf("expected error %v, got %v", ErrDependencyCycle, err)
+	}
+
+	for _, part := range []string{
+		"A -> B -> C -> A",
+		"main.go:32:11: NewA requires B (NewB) through parameter 'b'",
+		"main.go:39:11: NewB requires C (NewC) through parameter 'store'",
+		"main.go:45:11: NewC requires A (NewA) through parameter 'plugins'",
+	} {
+		if !strings.Contains(err.Error(), part) {
+			t.Errorf("expected error to contain %q, got: %v", part, err)
+		}
+	}
+}
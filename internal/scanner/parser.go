@@ -19,8 +19,10 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	"go/token"
 	"go/types"
 	"log/slog"
+	"maps"
 	"math"
 	"reflect"
 	"slices"
@@ -41,82 +43,257 @@ var (
 	ErrNoImplementation     = errors.New("no component implements interface")
 	ErrInvalidSlice         = errors.New("invalid slice")
 	ErrInvalidMetadata      = errors.New("invalid metadata")
+	ErrUnknownQualifier     = errors.New("no component with qualifier")
+	ErrQualifierMismatch    = errors.New("qualified component does not match parameter")
+	ErrInvalidGeneric       = errors.New("invalid generic component")
+	ErrInvalidMap           = errors.New("invalid map")
+	ErrDuplicateKey         = errors.New("duplicate map key")
+	ErrScopeMismatch        = errors.New("component depends on a shorter-lived component")
+	ErrConflictingMetadata  = errors.New("conflicting metadata")
+	ErrInvalidDecorator     = errors.New("invalid decorator")
+	ErrNoRoots              = errors.New("no roots declared")
+	ErrDependencyCycle      = errors.New("dependency cycle")
+	ErrUnknownOption        = errors.New("unknown flora option")
+	ErrIgnoredDirective     = errors.New("ignored magic comment")
+	ErrDeprecatedOption     = errors.New("deprecated flora option")
 )
 
 const (
 	ScopeSingleton = "singleton"
 	ScopePrototype = "prototype"
+	ScopeLazy      = "lazy"
+	ScopeRequest   = "request"
+
+	ConditionalOnMissing = "missing"
 
 	ComponentMarker     = "github.com/soner3/flora.Component"
 	ConfigurationMarker = "github.com/soner3/flora.Configuration"
+	LazyTypeName        = "github.com/soner3/flora.Lazy"
+	FloraPackagePath    = "github.com/soner3/flora"
+
+	// ProviderDirective marks a package-level function as a provider: '// flora:provider'
+	ProviderDirective = "provider"
+
+	// ExposeAll turns every component into a container field
+	ExposeAll = "all"
+	// ExposeRootsOnly only exposes the components nothing else depends on
+	// and those tagged 'expose'
+	ExposeRootsOnly = "roots-only"
 )
 
 var scopes = []string{
 	ScopeSingleton,
 	ScopePrototype,
+	ScopeLazy,
+	ScopeRequest,
+}
+
+// tagOptions lists the options of flora tags and magic comments and whether they take a value
+var tagOptions = map[string]bool{
+	"autowire":    false,
+	"primary":     false,
+	"expose":      false,
+	"internal":    false,
+	"name":        true,
+	"qualifier":   true,
+	"profile":     true,
+	"module":      true,
+	"conditional": true,
+	"bind":        true,
+	"nobind":      true,
+	"decorates":   true,
+	"key":         true,
+	"constructor": true,
+	"scope":       true,
+	"order":       true,
 }
 
+// repeatableOptions may be given more than once, every other option only once
+var repeatableOptions = []string{"qualifier", "bind", "nobind"}
+
 var markers = []string{
 	ComponentMarker,
 	ConfigurationMarker,
 }
 
 type scannedComponent struct {
-	Metadata *engine.ComponentMetadata
-	PtrType  *types.Pointer
+	Metadata         *engine.ComponentMetadata
+	PtrType          *types.Pointer
+	Signature        *types.Signature
+	LazyType         types.Type
+	Binds            []*types.Named
+	NoBinds          []*types.Named
+	Decorates        types.Type
+	NeededInterfaces map[string]types.Type
+	NeededSlices     map[string]types.Type
+	NeededMaps       map[string]types.Type
+	// Shared marks the Configuration instance its methods are called on,
+	// it is never bound to interfaces, slices or maps
+	Shared bool
 }
 
 type componentInfo struct {
 	Pkg        *packages.Package
 	Name       string
 	TypeName   *types.TypeName
+	Type       types.Type
+	TypeArgs   []types.Type
 	StructType *types.Struct
 	Marker     string
 	Tag        string
+	Pos        token.Pos
+	TagPos     token.Pos
 }
 
 var log = slog.With("pkg", "scanner")
 
+// Options controls which parts of the scanned code end up in the container
+type Options struct {
+	// Profile selects the active profile. Components without a profile
+	// are active in every profile.
+	Profile string
+	// Tags, GOOS and GOARCH select the files that are scanned, exactly
+	// like they select the files that are built.
+	Tags   []string
+	GOOS   string
+	GOARCH string
+	// Expose selects the components that become container fields, see the
+	// Expose constants. Empty exposes all of them.
+	Expose string
+}
+
 // ParsePackages parses the given packages and returns a GeneratorContext
 // containing the parsed components and slice bindings
-func ParsePackages(pkgs []*packages.Package) (*engine.GeneratorContext, error) {
-	log.Debug("Parsing components from packages", "package_count", len(pkgs))
+func ParsePackages(pkgs []*packages.Package, opts Options) (*engine.GeneratorContext, error) {
+	log.Debug("Parsing components from packages", "package_count", len(pkgs), "profile", opts.Profile)
 
 	compInfos := parseMarkedComponents(pkgs)
 
+	// packages.Load positions all packages in one file set
+	fset := token.NewFileSet()
+	if len(pkgs) > 0 {
+		fset = pkgs[0].Fset
+	}
+
 	log.Debug("Marked components found", "count", len(*compInfos))
 
-	neededInterfaces := make(map[string]types.Type)
-	neededSlices := make(map[string]types.Type)
+	for _, warning := range ignoredDirectives(pkgs, *compInfos) {
+		log.Warn(warning.Error())
+	}
+	for _, warning := range deprecatedTags(*compInfos) {
+		log.Warn(warning.Error())
+	}
+
 	scannedComponents := make([]*scannedComponent, 0)
+	var genericInfos []componentInfo
 
+	// Every component is processed so that all invalid ones are reported at once
+	var processErrs []error
 	for _, compInfo := range *compInfos {
 		switch compInfo.Marker {
 		case ComponentMarker:
-			scannedComp, err := processComponent(&compInfo, &neededInterfaces, &neededSlices)
+			if isGeneric(compInfo.TypeName) {
+				genericInfos = append(genericInfos, compInfo)
+				continue
+			}
+			scannedComp, err := processComponent(&compInfo, opts.Profile)
 			if err != nil {
-				return nil, err
+				processErrs = append(processErrs, err)
+				continue
+			}
+			if scannedComp != nil {
+				scannedComponents = append(scannedComponents, scannedComp)
 			}
-			scannedComponents = append(scannedComponents, scannedComp)
 		case ConfigurationMarker:
-			scannedComps, err := processConfiguration(&compInfo, &neededInterfaces, &neededSlices)
+			if isGeneric(compInfo.TypeName) {
+				processErrs = append(processErrs, errs.WrapAt(compInfo.Pkg.Fset.Position(compInfo.Pos), ErrInvalidGeneric,
+					"configuration '%s' in package '%s' must not be generic", compInfo.Name, compInfo.Pkg.Name))
+				continue
+			}
+			scannedComps, err := processConfiguration(&compInfo, opts.Profile)
 			if err != nil {
-				return nil, err
+				processErrs = append(processErrs, err)
+				continue
 			}
 			scannedComponents = append(scannedComponents, scannedComps...)
+		case ProviderDirective:
+			scannedComp, err := processProvider(&compInfo, opts.Profile)
+			if err != nil {
+				processErrs = append(processErrs, err)
+				continue
+			}
+			if scannedComp != nil {
+				scannedComponents = append(scannedComponents, scannedComp)
+			}
 		}
 
 	}
+	if len(processErrs) > 0 {
+		return nil, errors.Join(processErrs...)
+	}
 
-	log.Debug("Resolving interface implementations", "interfaces_needed", len(neededInterfaces))
-	if err := bindInterfacesToComponents(scannedComponents, neededInterfaces); err != nil {
+	log.Debug("Instantiating generic components", "generic_count", len(genericInfos))
+	scannedComponents, err := instantiateGenericComponents(genericInfos, scannedComponents, opts.Profile)
+	if err != nil {
+		return nil, err
+	}
+
+	scannedComponents = dropConditionalComponents(scannedComponents)
+
+	log.Debug("Chaining decorators")
+	if err := applyDecorators(scannedComponents); err != nil {
 		return nil, err
 	}
 
+	neededInterfaces := make(map[string]types.Type)
+	neededSlices := make(map[string]types.Type)
+	neededMaps := make(map[string]types.Type)
+	for _, comp := range scannedComponents {
+		maps.Copy(neededInterfaces, comp.NeededInterfaces)
+		for _, bound := range comp.Binds {
+			neededInterfaces[bound.String()] = bound
+		}
+		maps.Copy(neededSlices, comp.NeededSlices)
+		maps.Copy(neededMaps, comp.NeededMaps)
+	}
+
+	// The bindings do not depend on each other, so all their errors are reported together
+	var bindErrs []error
+
+	log.Debug("Resolving qualified parameters")
+	if err := bindQualifiersToComponents(scannedComponents, fset); err != nil {
+		bindErrs = append(bindErrs, err)
+	}
+
+	log.Debug("Resolving interface implementations", "interfaces_needed", len(neededInterfaces))
+	if err := bindInterfacesToComponents(scannedComponents, neededInterfaces, fset); err != nil {
+		bindErrs = append(bindErrs, err)
+	}
+
 	log.Debug("Resolving slice bindings", "slices_needed", len(neededSlices))
 
-	sliceBindings, err := bindSlicesToComponents(scannedComponents, neededSlices)
+	sliceBindings, err := bindSlicesToComponents(scannedComponents, neededSlices, fset)
+	if err != nil {
+		bindErrs = append(bindErrs, err)
+	}
+
+	log.Debug("Resolving map bindings", "maps_needed", len(neededMaps))
+
+	mapBindings, err := bindMapsToComponents(scannedComponents, neededMaps, fset)
 	if err != nil {
+		bindErrs = append(bindErrs, err)
+	}
+
+	if len(bindErrs) > 0 {
+		return nil, errors.Join(bindErrs...)
+	}
+
+	log.Debug("Resolving dependencies")
+	if err := resolveDependencies(scannedComponents, fset); err != nil {
+		return nil, err
+	}
+
+	if err := detectCycles(scannedComponents, fset); err != nil {
 		return nil, err
 	}
 
@@ -125,11 +302,30 @@ func ParsePackages(pkgs []*packages.Package) (*engine.GeneratorContext, error) {
 		finalMetadata = append(finalMetadata, comp.Metadata)
 	}
 
-	log.Debug("Successfully parsed all components", "total", len(finalMetadata), "slices", len(sliceBindings))
+	lifecycle := lifecycleOrder(finalMetadata)
+
+	modules, err := moduleClosures(finalMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := hideComponents(finalMetadata, opts.Expose); err != nil {
+		return nil, err
+	}
+
+	log.Debug("Successfully parsed all components", "total", len(finalMetadata), "slices", len(sliceBindings), "maps", len(mapBindings))
 
 	return &engine.GeneratorContext{
 		Components:    finalMetadata,
 		SliceBindings: sliceBindings,
+		MapBindings:   mapBindings,
+		Lifecycle:     lifecycle,
+		Modules:       modules,
+		Expose:        opts.Expose,
+		Profile:       opts.Profile,
+		Tags:          opts.Tags,
+		GOOS:          opts.GOOS,
+		GOARCH:        opts.GOARCH,
 	}, nil
 
 }
@@ -145,43 +341,69 @@ func parseMarkedComponents(pkgs []*packages.Package) *[]componentInfo {
 			obj := scope.Lookup(name)
 			if typeName, ok := obj.(*types.TypeName); ok {
 				if structType, ok := typeName.Type().Underlying().(*types.Struct); ok {
-					isComponent, marker, tag := isMarkedWith(structType)
+					isComponent, marker, tag, tagPos := isMarkedWith(structType)
 
 					if isComponent {
 						components = append(components, componentInfo{
 							Pkg:        pkg,
 							Name:       name,
 							TypeName:   typeName,
+							Type:       typeName.Type(),
 							StructType: structType,
 							Marker:     marker,
 							Tag:        tag,
+							Pos:        typeName.Pos(),
+							TagPos:     tagPos,
 						})
 					}
 				}
 			}
 		}
+
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				funcDecl, ok := decl.(*ast.FuncDecl)
+				if !ok || funcDecl.Recv != nil {
+					continue
+				}
+				directive, directivePos := floraDirective(funcDecl.Doc)
+				tag, isProvider := cutProviderDirective(directive)
+				if !isProvider {
+					continue
+				}
+				components = append(components, componentInfo{
+					Pkg:    pkg,
+					Name:   funcDecl.Name.Name,
+					Marker: ProviderDirective,
+					Tag:    tag,
+					Pos:    funcDecl.Name.Pos(),
+					TagPos: directivePos,
+				})
+			}
+		}
 	}
 
 	return &components
 }
 
 // isMarkedWith checks if the struct is marked with any of the flora markers
-// and returns the marker and tag
-func isMarkedWith(structType *types.Struct) (bool, string, string) {
+// and returns the marker, its tag and the position of the marker field
+func isMarkedWith(structType *types.Struct) (bool, string, string, token.Pos) {
 	for i := 0; i < structType.NumFields(); i++ {
 		field := structType.Field(i)
 		for _, marker := range markers {
 			if field.Anonymous() && field.Type().String() == marker {
-				return true, marker, structType.Tag(i)
+				return true, marker, structType.Tag(i), field.Pos()
 			}
 		}
 
 	}
-	return false, "", ""
+	return false, "", "", token.NoPos
 }
 
-// parseFloraTag parses the flora tag and sets the metadata accordingly
-func parseFloraTag(rawTag string, metadata *engine.ComponentMetadata) error {
+// parseFloraTag parses the flora tag and sets the metadata accordingly.
+// pos is the position of the tag or magic comment errors point at.
+func parseFloraTag(rawTag string, pos token.Position, metadata *engine.ComponentMetadata) error {
 	if metadata.ConfigStructName == "" {
 		metadata.ConstructorName = "New" + metadata.StructName
 	}
@@ -200,86 +422,439 @@ func parseFloraTag(rawTag string, metadata *engine.ComponentMetadata) error {
 		return nil
 	}
 
-	parts := strings.SplitSeq(val, ",")
-	for part := range parts {
+	seen := make(map[string]bool)
+	for part := range strings.SplitSeq(val, ",") {
 		part = strings.TrimSpace(part)
 		if part == "" {
 			continue
 		}
+
+		key, value, hasValue := strings.Cut(part, "=")
+		key = strings.TrimSpace(key)
+		if isPositionalConstructor(part) {
+			key, value, hasValue = "constructor", part, true
+		}
+		takesValue, known := tagOptions[key]
 		switch {
-		case part == "primary":
+		case !known:
+			hint := didYouMean(key, slices.Sorted(maps.Keys(tagOptions)))
+			chainErr := fmt.Errorf("%w: %s", ErrUnknownOption, key)
+			return errs.WrapAt(pos, chainErr, "unknown flora option '%s' for component '%s' in package '%s'%s", key, metadata.StructName, metadata.PackageName, hint)
+		case takesValue && !hasValue:
+			return errs.WrapAt(pos, ErrInvalidMetadata, "flora option '%s' of component '%s' in package '%s' requires a value ('%s=...')", key, metadata.StructName, metadata.PackageName, key)
+		case !takesValue && hasValue:
+			return errs.WrapAt(pos, ErrInvalidMetadata, "flora option '%s' of component '%s' in package '%s' does not take a value", key, metadata.StructName, metadata.PackageName)
+		case seen[key] && !slices.Contains(repeatableOptions, key):
+			return errs.WrapAt(pos, ErrInvalidMetadata, "duplicate flora option '%s' for component '%s' in package '%s'", key, metadata.StructName, metadata.PackageName)
+		}
+		seen[key] = true
+
+		switch key {
+		case "autowire":
+			metadata.Autowire = true
+		case "primary":
 			metadata.IsPrimary = true
-		case strings.HasPrefix(part, "constructor="):
-			if metadata.ConfigStructName == "" {
-				metadata.ConstructorName = strings.TrimPrefix(part, "constructor=")
+		case "expose":
+			metadata.Expose = true
+		case "internal":
+			metadata.Internal = true
+		case "name":
+			if !token.IsIdentifier(value) {
+				return errs.WrapAt(pos, ErrInvalidMetadata, "invalid name '%s' for component '%s' in package '%s' (must be a valid Go identifier)", value, metadata.StructName, metadata.PackageName)
 			}
-
-			if err := isExported(metadata); err != nil {
+			metadata.Qualifier = value
+		case "qualifier":
+			paramName, name, ok := strings.Cut(value, ":")
+			if !ok || !token.IsIdentifier(paramName) || !token.IsIdentifier(name) {
+				return errs.WrapAt(pos, ErrInvalidMetadata, "invalid qualifier '%s' for component '%s' in package '%s' (expected 'qualifier=<param>:<name>')", part, metadata.StructName, metadata.PackageName)
+			}
+			if metadata.ParamQualifiers == nil {
+				metadata.ParamQualifiers = make(map[string]string)
+			}
+			if _, exists := metadata.ParamQualifiers[paramName]; exists {
+				return errs.WrapAt(pos, ErrInvalidMetadata, "duplicate qualifier for parameter '%s' of component '%s' in package '%s'", paramName, metadata.StructName, metadata.PackageName)
+			}
+			metadata.ParamQualifiers[paramName] = name
+		case "profile":
+			for profile := range strings.SplitSeq(value, "|") {
+				profile = strings.TrimSpace(profile)
+				if !token.IsIdentifier(strings.TrimPrefix(profile, "!")) {
+					return errs.WrapAt(pos, ErrInvalidMetadata, "invalid profile '%s' for component '%s' in package '%s' (must be a valid Go identifier, optionally negated with '!')", profile, metadata.StructName, metadata.PackageName)
+				}
+				metadata.Profiles = append(metadata.Profiles, profile)
+			}
+		case "module":
+			for module := range strings.SplitSeq(value, "|") {
+				module = strings.TrimSpace(module)
+				if !token.IsIdentifier(module) {
+					return errs.WrapAt(pos, ErrInvalidMetadata, "invalid module '%s' for component '%s' in package '%s' (must be a valid Go identifier)", module, metadata.StructName, metadata.PackageName)
+				}
+				metadata.Modules = append(metadata.Modules, module)
+			}
+		case "conditional":
+			if value != ConditionalOnMissing {
+				return errs.WrapAt(pos, ErrInvalidMetadata, "invalid condition '%s' for component '%s' in package '%s' (supported: '%s')", value, metadata.StructName, metadata.PackageName, ConditionalOnMissing)
+			}
+			metadata.Conditional = value
+		case "bind", "nobind":
+			pkgName, name, qualified := strings.Cut(value, ".")
+			if !token.IsIdentifier(pkgName) || (qualified && !token.IsIdentifier(name)) {
+				return errs.WrapAt(pos, ErrInvalidMetadata, "invalid interface '%s' in '%s' for component '%s' in package '%s' (expected '%s=Iface' or '%s=pkg.Iface')",
+					value, key, metadata.StructName, metadata.PackageName, key, key)
+			}
+			if key == "bind" {
+				metadata.Binds = append(metadata.Binds, value)
+			} else {
+				metadata.NoBinds = append(metadata.NoBinds, value)
+			}
+		case "decorates":
+			ref := strings.TrimPrefix(value, "*")
+			pkgName, name, qualified := strings.Cut(ref, ".")
+			if !token.IsIdentifier(pkgName) || (qualified && !token.IsIdentifier(name)) {
+				return errs.WrapAt(pos, ErrInvalidMetadata, "invalid type '%s' in 'decorates' for component '%s' in package '%s' (expected 'decorates=Type' or 'decorates=pkg.Type')",
+					ref, metadata.StructName, metadata.PackageName)
+			}
+			metadata.Decorates = ref
+		case "key":
+			if value == "" {
+				return errs.WrapAt(pos, ErrInvalidMetadata, "empty key for component '%s' in package '%s'", metadata.StructName, metadata.PackageName)
+			}
+			metadata.Key = value
+		case "constructor":
+			if err := isExported(&engine.ComponentMetadata{ConstructorName: value, StructName: metadata.StructName, PackageName: metadata.PackageName, Position: pos}); err != nil {
 				return err
 			}
-		case strings.HasPrefix(part, "scope="):
-			scope := strings.TrimPrefix(part, "scope=")
-			if !slices.Contains(scopes, scope) {
-				return errs.Wrap(ErrInvalidMetadata, "invalid scope '%s' for component '%s' in package '%s'", scope, metadata.StructName, metadata.PackageName)
+			if metadata.ConfigStructName == "" {
+				metadata.ConstructorName = value
+			}
+		case "scope":
+			if !slices.Contains(scopes, value) {
+				return errs.WrapAt(pos, ErrInvalidMetadata, "invalid scope '%s' for component '%s' in package '%s' (supported: '%s')%s",
+					value, metadata.StructName, metadata.PackageName, strings.Join(scopes, "', '"), didYouMean(value, scopes))
 			}
-			metadata.Scope = scope
-		case strings.HasPrefix(part, "order="):
-			orderStr := strings.TrimPrefix(part, "order=")
-			order, err := strconv.Atoi(orderStr)
+			metadata.Scope = value
+		case "order":
+			order, err := strconv.Atoi(value)
 			if err != nil {
-				return errs.Wrap(ErrInvalidMetadata, "invalid order '%s' for component '%s' in package '%s' (must be an integer)", orderStr, metadata.StructName, metadata.PackageName)
+				return errs.WrapAt(pos, ErrInvalidMetadata, "invalid order '%s' for component '%s' in package '%s' (must be an integer)", value, metadata.StructName, metadata.PackageName)
 			}
 			metadata.Order = order
-		default:
-			if metadata.ConfigStructName == "" {
-				metadata.ConstructorName = part
+		}
+	}
+
+	return nil
+}
+
+// floraTagKeys returns the options a flora tag sets explicitly, without validating them
+func floraTagKeys(rawTag string) map[string]bool {
+	keys := make(map[string]bool)
+	for part := range strings.SplitSeq(reflect.StructTag(rawTag).Get("flora"), ",") {
+		if isPositionalConstructor(strings.TrimSpace(part)) {
+			keys["constructor"] = true
+		} else if key, _, _ := strings.Cut(part, "="); strings.TrimSpace(key) != "" {
+			keys[strings.TrimSpace(key)] = true
+		}
+	}
+	return keys
+}
+
+// isPositionalConstructor reports whether a tag option names the constructor without
+// 'constructor=', e.g. 'flora:"NewCache"'. The form is deprecated, but still read
+// until the next release. All options are lower case, so it never shadows one.
+func isPositionalConstructor(part string) bool {
+	return token.IsIdentifier(part) && token.IsExported(part)
+}
+
+// deprecatedTags warns about the struct tags that name the constructor without 'constructor='
+func deprecatedTags(compInfos []componentInfo) []error {
+	var warnings []error
+	for _, compInfo := range compInfos {
+		if compInfo.Marker != ComponentMarker && compInfo.Marker != ConfigurationMarker {
+			continue
+		}
+		for part := range strings.SplitSeq(reflect.StructTag(compInfo.Tag).Get("flora"), ",") {
+			if part = strings.TrimSpace(part); isPositionalConstructor(part) {
+				chainErr := fmt.Errorf("%w: %s", ErrDeprecatedOption, part)
+				warnings = append(warnings, errs.WrapAt(compInfo.Pkg.Fset.Position(compInfo.TagPos), chainErr,
+					"flora tag '%s' of '%s' in package '%s' is deprecated and will be rejected in the next release, use 'constructor=%s'",
+					part, compInfo.Name, compInfo.Pkg.Name, part))
 			}
+		}
+	}
+	return warnings
+}
 
-			if err := isExported(metadata); err != nil {
-				return err
+// isActiveInProfile reports whether a component takes part in the given profile.
+// Components without a profile are always active, '!name' excludes a profile.
+func isActiveInProfile(metadata *engine.ComponentMetadata, profile string) bool {
+	if len(metadata.Profiles) == 0 {
+		return true
+	}
+
+	for _, p := range metadata.Profiles {
+		if excluded, ok := strings.CutPrefix(p, "!"); ok {
+			if excluded != profile {
+				return true
 			}
+		} else if p == profile {
+			return true
 		}
 	}
 
-	return nil
+	return false
 }
 
-// processComponent processes a component and returns a scannedComponent
-func processComponent(compInfo *componentInfo, neededInterfaces, neededSlices *map[string]types.Type) (*scannedComponent, error) {
+// processComponent processes a component and returns a scannedComponent.
+// It returns nil if the component is not active in the given profile.
+func processComponent(compInfo *componentInfo, profile string) (*scannedComponent, error) {
 	metadata := &engine.ComponentMetadata{
 		StructName:  compInfo.Name,
 		PackageName: compInfo.Pkg.Name,
 		PackagePath: compInfo.Pkg.PkgPath,
+		Position:    compInfo.Pkg.Fset.Position(compInfo.Pos),
 	}
 
-	if err := parseFloraTag(compInfo.Tag, metadata); err != nil {
+	if err := parseFloraTag(compInfo.Tag, compInfo.Pkg.Fset.Position(compInfo.TagPos), metadata); err != nil {
 		return nil, err
 	}
 
+	if err := mergeConstructorDirective(compInfo, metadata); err != nil {
+		return nil, err
+	}
+
+	if !isActiveInProfile(metadata, profile) {
+		log.Debug("Skipping component outside of active profile", "component", metadata.StructName, "profiles", metadata.Profiles)
+		return nil, nil
+	}
+
 	obj := compInfo.Pkg.Types.Scope().Lookup(metadata.ConstructorName)
 
-	err := processProviderFunc(compInfo, metadata, obj, neededInterfaces, neededSlices)
+	injectObj, err := injectionConstructor(compInfo, metadata, obj)
+	if err != nil {
+		return nil, err
+	}
+	if injectObj != nil {
+		obj = injectObj
+	}
+
+	neededInterfaces := make(map[string]types.Type)
+	neededSlices := make(map[string]types.Type)
+	neededMaps := make(map[string]types.Type)
+	sig, err := processProviderFunc(compInfo, metadata, obj, &neededInterfaces, &neededSlices, &neededMaps)
+	if err != nil {
+		return nil, err
+	}
+
+	lazyType, err := lazyTypeOf(compInfo, metadata, sig)
+	if err != nil {
+		return nil, err
+	}
+
+	setLifecycle(compInfo, metadata, sig)
+
+	ptrType := types.NewPointer(compInfo.Type)
+	binds, noBinds, err := resolveBindings(compInfo, metadata, ptrType)
+	if err != nil {
+		return nil, err
+	}
+
+	decorates, err := resolveDecorates(compInfo, metadata, sig)
 	if err != nil {
 		return nil, err
 	}
 
 	return &scannedComponent{
-		Metadata: metadata,
-		PtrType:  types.NewPointer(compInfo.TypeName.Type()),
+		Metadata:         metadata,
+		PtrType:          ptrType,
+		Signature:        sig,
+		LazyType:         lazyType,
+		Binds:            binds,
+		NoBinds:          noBinds,
+		Decorates:        decorates,
+		NeededInterfaces: neededInterfaces,
+		NeededSlices:     neededSlices,
+		NeededMaps:       neededMaps,
 	}, nil
 
 }
 
+// mergeConstructorDirective merges the '// flora:' magic comment on the constructor of a
+// component into the metadata from its struct tag. Settings made in both places must agree.
+func mergeConstructorDirective(compInfo *componentInfo, metadata *engine.ComponentMetadata) error {
+	var directive string
+	var directivePos token.Pos
+	for _, file := range compInfo.Pkg.Syntax {
+		for _, decl := range file.Decls {
+			if funcDecl, ok := decl.(*ast.FuncDecl); ok && funcDecl.Recv == nil && funcDecl.Name.Name == metadata.ConstructorName {
+				directive, directivePos = floraDirective(funcDecl.Doc)
+			}
+		}
+	}
+	if directive == "" {
+		return nil
+	}
+
+	fromComment := &engine.ComponentMetadata{
+		StructName:  metadata.StructName,
+		PackageName: metadata.PackageName,
+		PackagePath: metadata.PackagePath,
+	}
+	pos := compInfo.Pkg.Fset.Position(directivePos)
+	if err := parseFloraTag(fmt.Sprintf(`flora:"%s"`, directive), pos, fromComment); err != nil {
+		return err
+	}
+
+	if fromComment.ConstructorName != metadata.ConstructorName && fromComment.ConstructorName != "New"+metadata.StructName {
+		return errs.WrapAt(pos, ErrInvalidMetadata, "the magic comment on '%s' of component '%s' in package '%s' cannot set a constructor",
+			metadata.ConstructorName, metadata.StructName, metadata.PackageName)
+	}
+
+	conflict := func(key string, tagValue, commentValue any) error {
+		chainErr := fmt.Errorf("%w: %s", ErrConflictingMetadata, key)
+		return errs.WrapAt(pos, chainErr, "component '%s' in package '%s' sets '%s' to '%v' in its struct tag, but to '%v' on constructor '%s'",
+			metadata.StructName, metadata.PackageName, key, tagValue, commentValue, metadata.ConstructorName)
+	}
+
+	metadata.IsPrimary = metadata.IsPrimary || fromComment.IsPrimary
+	metadata.Autowire = metadata.Autowire || fromComment.Autowire
+	metadata.Expose = metadata.Expose || fromComment.Expose
+	metadata.Internal = metadata.Internal || fromComment.Internal
+	metadata.Binds = append(metadata.Binds, fromComment.Binds...)
+	metadata.NoBinds = append(metadata.NoBinds, fromComment.NoBinds...)
+
+	// Scope and order have defaults, only explicit settings on both sides can conflict
+	inTag, inComment := floraTagKeys(compInfo.Tag), floraTagKeys(fmt.Sprintf(`flora:"%s"`, directive))
+	if inComment["scope"] {
+		if inTag["scope"] && metadata.Scope != fromComment.Scope {
+			return conflict("scope", metadata.Scope, fromComment.Scope)
+		}
+		metadata.Scope = fromComment.Scope
+	}
+	if inComment["order"] {
+		if inTag["order"] && metadata.Order != fromComment.Order {
+			return conflict("order", metadata.Order, fromComment.Order)
+		}
+		metadata.Order = fromComment.Order
+	}
+	if len(fromComment.Profiles) > 0 {
+		if len(metadata.Profiles) > 0 && !slices.Equal(metadata.Profiles, fromComment.Profiles) {
+			return conflict("profile", strings.Join(metadata.Profiles, "|"), strings.Join(fromComment.Profiles, "|"))
+		}
+		metadata.Profiles = fromComment.Profiles
+	}
+	if len(fromComment.Modules) > 0 {
+		if len(metadata.Modules) > 0 && !slices.Equal(metadata.Modules, fromComment.Modules) {
+			return conflict("module", strings.Join(metadata.Modules, "|"), strings.Join(fromComment.Modules, "|"))
+		}
+		metadata.Modules = fromComment.Modules
+	}
+
+	for _, field := range []struct {
+		key                    string
+		tagValue, commentValue *string
+	}{
+		{"name", &metadata.Qualifier, &fromComment.Qualifier},
+		{"key", &metadata.Key, &fromComment.Key},
+		{"conditional", &metadata.Conditional, &fromComment.Conditional},
+	} {
+		if *field.commentValue == "" {
+			continue
+		}
+		if *field.tagValue != "" && *field.tagValue != *field.commentValue {
+			return conflict(field.key, *field.tagValue, *field.commentValue)
+		}
+		*field.tagValue = *field.commentValue
+	}
+
+	for _, param := range slices.Sorted(maps.Keys(fromComment.ParamQualifiers)) {
+		name := fromComment.ParamQualifiers[param]
+		if existing, ok := metadata.ParamQualifiers[param]; ok && existing != name {
+			return conflict("qualifier="+param, existing, name)
+		}
+		if metadata.ParamQualifiers == nil {
+			metadata.ParamQualifiers = make(map[string]string)
+		}
+		metadata.ParamQualifiers[param] = name
+	}
+
+	return nil
+}
+
+// injectionConstructor builds the signature of the constructor the generator writes for
+// components using field injection, so they run through the same checks as hand-written ones.
+// Fields tagged 'flora:"inject"' are injected, or every exported, non-embedded field with 'autowire'
+// on the marker unless tagged 'flora:"-"'. It returns nil if the component injects no fields.
+func injectionConstructor(compInfo *componentInfo, metadata *engine.ComponentMetadata, declared types.Object) (types.Object, error) {
+	var params []*types.Var
+
+	for i := 0; i < compInfo.StructType.NumFields(); i++ {
+		field := compInfo.StructType.Field(i)
+		if field.Embedded() && slices.Contains(markers, field.Type().String()) {
+			continue
+		}
+
+		tag := reflect.StructTag(compInfo.StructType.Tag(i)).Get("flora")
+		switch tag {
+		case "inject":
+		case "-":
+			continue
+		case "":
+			if !metadata.Autowire || !field.Exported() || field.Embedded() {
+				continue
+			}
+		default:
+			return nil, errs.WrapAt(compInfo.Pkg.Fset.Position(field.Pos()), ErrInvalidMetadata, "invalid flora tag '%s' on field '%s' of component '%s' in package '%s' (expected 'inject' or '-')",
+				tag, field.Name(), metadata.StructName, metadata.PackageName)
+		}
+
+		if !field.Exported() {
+			return nil, errs.WrapAt(compInfo.Pkg.Fset.Position(field.Pos()), ErrInvalidMetadata, "cannot inject unexported field '%s' of component '%s' in package '%s'",
+				field.Name(), metadata.StructName, metadata.PackageName)
+		}
+
+		params = append(params, types.NewParam(field.Pos(), compInfo.Pkg.Types, field.Name(), field.Type()))
+		metadata.InjectFields = append(metadata.InjectFields, field.Name())
+	}
+
+	if len(params) == 0 {
+		return nil, nil
+	}
+
+	if len(compInfo.TypeArgs) > 0 {
+		chainErr := fmt.Errorf("%w: %s", ErrInvalidGeneric, metadata.StructName)
+		return nil, errs.WrapAt(metadata.Position, chainErr, "generic component '%s' in package '%s' cannot use field injection, write a constructor instead",
+			metadata.StructName, metadata.PackageName)
+	}
+
+	if declared != nil || metadata.ConstructorName != "New"+metadata.StructName {
+		chainErr := fmt.Errorf("%w: %s", ErrInvalidProviderFunc, metadata.ConstructorName)
+		return nil, errs.WrapAt(metadata.Position, chainErr, "component '%s' in package '%s' uses field injection and constructor '%s', remove one of them",
+			metadata.StructName, metadata.PackageName, metadata.ConstructorName)
+	}
+
+	result := types.NewParam(token.NoPos, compInfo.Pkg.Types, "", types.NewPointer(compInfo.Type))
+	sig := types.NewSignatureType(nil, nil, nil, types.NewTuple(params...), types.NewTuple(result), false)
+	metadata.ConstructorName = "Inject_" + metadata.StructName
+
+	return types.NewFunc(compInfo.TypeName.Pos(), compInfo.Pkg.Types, metadata.ConstructorName, sig), nil
+}
+
 // processProviderFunc validates the provider function and populates
-// the needed interfaces and slices in compInfo
-func processProviderFunc(compInfo *componentInfo, metadata *engine.ComponentMetadata, obj types.Object, neededInterfaces, neededSlices *map[string]types.Type) error {
+// the needed interfaces and slices in compInfo. Qualified parameters are
+// resolved by name later on and context.Context is passed to the container,
+// so neither requests a binding.
+func processProviderFunc(compInfo *componentInfo, metadata *engine.ComponentMetadata, obj types.Object, neededInterfaces, neededSlices, neededMaps *map[string]types.Type) (*types.Signature, error) {
 
 	sig, err := validateProviderFunc(compInfo, metadata, obj)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	for v := range sig.Params().Variables() {
+		if _, qualified := metadata.ParamQualifiers[v.Name()]; qualified || isContext(v.Type()) {
+			continue
+		}
+
 		paramType := v.Type()
 
 		if iface, isInterface := paramType.Underlying().(*types.Interface); isInterface {
@@ -297,16 +872,28 @@ func processProviderFunc(compInfo *componentInfo, metadata *engine.ComponentMeta
 			}
 		}
 
+		if mapType, isMap := paramType.(*types.Map); isMap {
+			elemType := mapType.Elem()
+			if iface, isInterface := elemType.Underlying().(*types.Interface); isInterface && !iface.Empty() {
+				if basic, ok := mapType.Key().(*types.Basic); !ok || basic.Kind() != types.String {
+					chainErr := fmt.Errorf("%w: %v", ErrInvalidMap, paramType)
+					return nil, errs.WrapAt(compInfo.Pkg.Fset.Position(v.Pos()), chainErr, "invalid map parameter '%s' of provider func '%s' for component '%s': only 'map[string]%s' is supported",
+						v.Name(), metadata.ConstructorName, metadata.StructName, elemType.String())
+				}
+				(*neededMaps)[elemType.String()] = elemType
+			}
+		}
+
 		if sigParam, isFunc := paramType.(*types.Signature); isFunc {
 
 			if sigParam.Params().Len() > 0 {
 				chainErr := fmt.Errorf("%w: %v", ErrInvalidProviderFunc, sigParam)
-				return errs.Wrap(chainErr, "invalid prototype provider func: '%s' for component '%s': prototype provider func must not have parameters",
+				return nil, errs.WrapAt(compInfo.Pkg.Fset.Position(v.Pos()), chainErr, "invalid prototype provider func: '%s' for component '%s': prototype provider func must not have parameters",
 					metadata.ConstructorName, metadata.StructName)
 			}
 
-			if _, _, err := validateReturnValues(sigParam, metadata.ConstructorName, metadata.StructName, metadata.PackageName); err != nil {
-				return err
+			if _, _, err := validateReturnValues(sigParam, compInfo.Pkg.Fset.Position(v.Pos()), metadata.ConstructorName, metadata.StructName, metadata.PackageName); err != nil {
+				return nil, err
 			}
 
 			retType := sigParam.Results().At(0).Type()
@@ -319,7 +906,7 @@ func processProviderFunc(compInfo *componentInfo, metadata *engine.ComponentMeta
 
 	}
 
-	return nil
+	return sig, nil
 }
 
 // validateProviderFunc validates the object is a provider function and
@@ -328,20 +915,38 @@ func validateProviderFunc(compInfo *componentInfo, metadata *engine.ComponentMet
 
 	if obj == nil {
 		chainErr := fmt.Errorf("%w: %v", ErrProviderFuncNotFound, obj)
-		return nil, errs.Wrap(chainErr, "provider '%s' not found for component '%s' in package '%s'",
-			metadata.ConstructorName, metadata.StructName, metadata.PackageName)
+		return nil, errs.WrapAt(metadata.Position, chainErr, "provider '%s' not found for component '%s' in package '%s'%s",
+			metadata.ConstructorName, metadata.StructName, metadata.PackageName, didYouMean(metadata.ConstructorName, funcNames(compInfo.Pkg.Types)))
 	}
 
+	pos := compInfo.Pkg.Fset.Position(obj.Pos())
+
 	funcObj, ok := obj.(*types.Func)
 	if !ok {
 		chainErr := fmt.Errorf("%w: %v", ErrInvalidProviderFunc, funcObj)
-		return nil, errs.Wrap(chainErr, "expected '%s' to be a function for component '%s', but it is a %T",
+		return nil, errs.WrapAt(pos, chainErr, "expected '%s' to be a function for component '%s', but it is a %T",
 			metadata.ConstructorName, metadata.StructName, obj)
 	}
 
 	sig := funcObj.Type().(*types.Signature)
 
-	hasCleanup, hasErr, err := validateReturnValues(sig, metadata.ConstructorName, metadata.StructName, metadata.PackageName)
+	if sig.TypeParams().Len() != len(compInfo.TypeArgs) {
+		chainErr := fmt.Errorf("%w: %v", ErrInvalidGeneric, sig)
+		return nil, errs.WrapAt(pos, chainErr, "provider func '%s' for component '%s' declares %d type parameters, but the component has %d",
+			metadata.ConstructorName, metadata.StructName, sig.TypeParams().Len(), len(compInfo.TypeArgs))
+	}
+
+	if len(compInfo.TypeArgs) > 0 {
+		inst, err := types.Instantiate(nil, sig, compInfo.TypeArgs, true)
+		if err != nil {
+			chainErr := fmt.Errorf("%w: %w", ErrInvalidGeneric, err)
+			return nil, errs.WrapAt(pos, chainErr, "cannot instantiate provider func '%s' for component '%s' with %v",
+				metadata.ConstructorName, metadata.StructName, compInfo.TypeArgs)
+		}
+		sig = inst.(*types.Signature)
+	}
+
+	hasCleanup, hasErr, err := validateReturnValues(sig, pos, metadata.ConstructorName, metadata.StructName, metadata.PackageName)
 	if err != nil {
 		return nil, err
 	}
@@ -362,17 +967,21 @@ func validateProviderFunc(compInfo *componentInfo, metadata *engine.ComponentMet
 		baseRetType = firstType
 	}
 
-	if metadata.ConfigStructName == "" {
-		if !types.Identical(baseRetType, compInfo.TypeName.Type()) {
+	if metadata.ConfigStructName == "" && metadata.ProviderPackagePath == "" {
+		if !types.Identical(baseRetType, compInfo.Type) {
 			chainErr := fmt.Errorf("%w: %v", ErrInvalidProviderFunc, firstType)
-			return nil, errs.Wrap(chainErr, "invalid provider func: '%s' returns '%s', but must return '%s' or '*%s'",
-				metadata.ConstructorName, firstType.String(), compInfo.TypeName.Name(), compInfo.TypeName.Name())
+			return nil, errs.WrapAt(pos, chainErr, "invalid provider func: '%s' returns '%s', but must return '%s' or '*%s'",
+				metadata.ConstructorName, firstType.String(), types.TypeString(compInfo.Type, types.RelativeTo(compInfo.Pkg.Types)), types.TypeString(compInfo.Type, types.RelativeTo(compInfo.Pkg.Types)))
+		}
+		if named, ok := baseRetType.(*types.Named); ok {
+			metadata.TypeArgs = typeArgsMetadata(named.TypeArgs())
 		}
 	} else {
 		if named, ok := baseRetType.(*types.Named); ok {
 			metadata.StructName = named.Obj().Name()
 			metadata.PackageName = named.Obj().Pkg().Name()
 			metadata.PackagePath = named.Obj().Pkg().Path()
+			metadata.TypeArgs = typeArgsMetadata(named.TypeArgs())
 		} else {
 			metadata.StructName = baseRetType.String()
 			metadata.PackageName = compInfo.Pkg.Name
@@ -393,12 +1002,19 @@ func validateProviderFunc(compInfo *componentInfo, metadata *engine.ComponentMet
 			baseParamType = paramType
 		}
 
-		if types.Identical(baseParamType, compInfo.TypeName.Type()) {
+		if types.Identical(baseParamType, compInfo.Type) && !(metadata.ConfigInstance && i == 0) {
 			chainErr := fmt.Errorf("%w: %v", ErrInvalidProviderFunc, paramType)
-			return nil, errs.Wrap(chainErr, "circular dependency: provider func '%s' for component '%s' cannot require its own type as a parameter",
+			return nil, errs.WrapAt(compInfo.Pkg.Fset.Position(param.Pos()), chainErr, "circular dependency: provider func '%s' for component '%s' cannot require its own type as a parameter",
 				metadata.ConstructorName, metadata.StructName)
 		}
 
+		// Lazy and prototype providers run after startup, when the context passed to the container may be cancelled
+		if isContext(paramType) && (metadata.Scope == ScopeLazy || metadata.Scope == ScopePrototype) {
+			chainErr := fmt.Errorf("%w: %v", ErrInvalidProviderFunc, paramType)
+			return nil, errs.WrapAt(compInfo.Pkg.Fset.Position(param.Pos()), chainErr, "provider func '%s' for %s component '%s' cannot take a context.Context, it runs after the container is initialized",
+				metadata.ConstructorName, metadata.Scope, metadata.StructName)
+		}
+
 		var imports []string
 		qualifier := func(p *types.Package) string {
 			if p.Path() != metadata.PackagePath {
@@ -410,15 +1026,160 @@ func validateProviderFunc(compInfo *componentInfo, metadata *engine.ComponentMet
 		paramTypeStr := types.TypeString(paramType, qualifier)
 
 		metadata.Params = append(metadata.Params, engine.ParamMetadata{
-			Name:    fmt.Sprintf("p%d", i),
-			Type:    paramTypeStr,
-			Imports: imports,
+			Name:      fmt.Sprintf("p%d", i),
+			Type:      paramTypeStr,
+			Imports:   imports,
+			Qualifier: metadata.ParamQualifiers[param.Name()],
 		})
 	}
 
+	for paramName := range metadata.ParamQualifiers {
+		found := false
+		for v := range params.Variables() {
+			if v.Name() == paramName {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errs.WrapAt(metadata.Position, ErrInvalidMetadata, "qualifier references unknown parameter '%s' of provider func '%s' for component '%s'",
+				paramName, metadata.ConstructorName, metadata.StructName)
+		}
+	}
+
 	return sig, nil
 }
 
+// isGeneric reports whether the type declares type parameters
+func isGeneric(typeName *types.TypeName) bool {
+	named, ok := typeName.Type().(*types.Named)
+	return ok && named.TypeParams().Len() > 0
+}
+
+// typeArgsMetadata renders the type arguments of an instantiated generic type
+// together with the imports they need
+func typeArgsMetadata(typeArgs *types.TypeList) []engine.TypeArgMetadata {
+	var result []engine.TypeArgMetadata
+	for t := range typeArgs.Types() {
+		var imports []string
+		qualifier := func(p *types.Package) string {
+			imports = append(imports, p.Path())
+			return p.Name()
+		}
+		result = append(result, engine.TypeArgMetadata{
+			Type:    types.TypeString(t, qualifier),
+			Imports: imports,
+		})
+	}
+	return result
+}
+
+// instantiateGenericComponents creates a component for every instantiation of a
+// generic component that is requested by a parameter or needed to implement a
+// generic interface. New instantiations can request further ones, so this runs
+// until no new instantiation is found. The errors of all instantiations are joined.
+func instantiateGenericComponents(genericInfos []componentInfo, components []*scannedComponent, profile string) ([]*scannedComponent, error) {
+	if len(genericInfos) == 0 {
+		return components, nil
+	}
+
+	seen := make(map[string]bool)
+	var errList []error
+
+	for {
+		var requested []*types.Named
+
+		request := func(named *types.Named) {
+			key := named.String()
+			if !seen[key] {
+				seen[key] = true
+				requested = append(requested, named)
+			}
+		}
+
+		for _, comp := range components {
+			for v := range comp.Signature.Params().Variables() {
+				if named := requestedInstance(v.Type(), genericInfos); named != nil {
+					request(named)
+				}
+			}
+
+			for _, neededType := range comp.NeededInterfaces {
+				iface := neededType.Underlying().(*types.Interface)
+				named, ok := neededType.(*types.Named)
+				if !ok || named.TypeArgs().Len() == 0 || slices.ContainsFunc(components, func(c *scannedComponent) bool {
+					return types.Implements(c.PtrType, iface)
+				}) {
+					continue
+				}
+
+				for _, info := range genericInfos {
+					inst, err := types.Instantiate(nil, info.TypeName.Type(), slices.Collect(named.TypeArgs().Types()), true)
+					if err != nil {
+						continue
+					}
+					if types.Implements(types.NewPointer(inst), iface) {
+						request(inst.(*types.Named))
+					}
+				}
+			}
+		}
+
+		if len(requested) == 0 {
+			return components, errors.Join(errList...)
+		}
+
+		for _, named := range requested {
+			idx := slices.IndexFunc(genericInfos, func(info componentInfo) bool {
+				return info.TypeName == named.Origin().Obj()
+			})
+			instInfo := genericInfos[idx]
+			instInfo.Type = named
+			instInfo.TypeArgs = slices.Collect(named.TypeArgs().Types())
+
+			log.Debug("Instantiating generic component", "component", named.String())
+
+			scannedComp, err := processComponent(&instInfo, profile)
+			if err != nil {
+				errList = append(errList, err)
+				continue
+			}
+			if scannedComp != nil {
+				components = append(components, scannedComp)
+			}
+		}
+	}
+}
+
+// requestedInstance returns the instantiated generic component a parameter
+// asks for, either directly, through a prototype factory or through flora.Lazy
+func requestedInstance(paramType types.Type, genericInfos []componentInfo) *types.Named {
+	if sig, ok := paramType.(*types.Signature); ok && sig.Results().Len() > 0 {
+		paramType = sig.Results().At(0).Type()
+	}
+	if ptr, ok := paramType.(*types.Pointer); ok {
+		paramType = ptr.Elem()
+	}
+	if named, ok := paramType.(*types.Named); ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path()+"."+named.Obj().Name() == LazyTypeName {
+		paramType = named.TypeArgs().At(0)
+		if ptr, ok := paramType.(*types.Pointer); ok {
+			paramType = ptr.Elem()
+		}
+	}
+
+	named, ok := paramType.(*types.Named)
+	if !ok || named.TypeArgs().Len() == 0 {
+		return nil
+	}
+
+	for _, info := range genericInfos {
+		if info.TypeName == named.Origin().Obj() {
+			return named
+		}
+	}
+	return nil
+}
+
 // isCleanupFunc checks if the type is a cleanup function (func())
 func isCleanupFunc(t types.Type) bool {
 	sig, ok := t.(*types.Signature)
@@ -428,18 +1189,305 @@ func isCleanupFunc(t types.Type) bool {
 	return sig.Params().Len() == 0 && sig.Results().Len() == 0
 }
 
-// bindInterfacesToComponents binds the needed interfaces to the components that implement them
-func bindInterfacesToComponents(components []*scannedComponent, neededInterfaces map[string]types.Type) error {
-	for neededName, neededType := range neededInterfaces {
+// providedType returns the type a component contributes to the graph:
+// the constructor result for singletons, the factory func for prototypes
+// and '*flora.Lazy[T]' for lazy components
+func providedType(comp *scannedComponent) types.Type {
+	switch comp.Metadata.Scope {
+	case ScopePrototype:
+		return types.NewSignatureType(nil, nil, nil, nil, comp.Signature.Results(), false)
+	case ScopeLazy:
+		return comp.LazyType
+	}
+	return comp.Signature.Results().At(0).Type()
+}
+
+// resolveBindings looks up the interfaces of 'bind=' and 'nobind=' as seen from
+// the package of the component and checks that bound interfaces are implemented
+func resolveBindings(compInfo *componentInfo, metadata *engine.ComponentMetadata, ptrType *types.Pointer) ([]*types.Named, []*types.Named, error) {
+	if len(metadata.Binds) > 0 && (metadata.Qualifier != "" || metadata.Scope == ScopeLazy) {
+		return nil, nil, errs.WrapAt(metadata.Position, ErrInvalidMetadata, "component '%s' in package '%s' cannot use 'bind=': named and lazy components are never bound to interfaces",
+			metadata.StructName, metadata.PackageName)
+	}
+
+	lookup := func(ref string) (*types.Named, error) {
+		named, ok := namedInterface(lookupType(compInfo, ref))
+		if !ok || named.TypeParams().Len() > 0 {
+			chainErr := fmt.Errorf("%w: %s", ErrInvalidInterface, ref)
+			return nil, errs.WrapAt(metadata.Position, chainErr, "'%s' of component '%s' in package '%s' is not a non-generic interface visible in package '%s'",
+				ref, metadata.StructName, metadata.PackageName, compInfo.Pkg.Name)
+		}
+		return named, nil
+	}
+
+	var binds, noBinds []*types.Named
+	for _, ref := range metadata.Binds {
+		named, err := lookup(ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !types.Implements(ptrType, named.Underlying().(*types.Interface)) {
+			chainErr := fmt.Errorf("%w: %s", ErrInvalidInterface, ref)
+			return nil, nil, errs.WrapAt(metadata.Position, chainErr, "component '%s' in package '%s' does not implement '%s' it binds to", metadata.StructName, metadata.PackageName, ref)
+		}
+		binds = append(binds, named)
+	}
+	for _, ref := range metadata.NoBinds {
+		named, err := lookup(ref)
+		if err != nil {
+			return nil, nil, err
+		}
+		noBinds = append(noBinds, named)
+	}
+
+	return binds, noBinds, nil
+}
+
+// lookupType resolves 'Type' or 'pkg.Type' as seen from the package of the component
+func lookupType(compInfo *componentInfo, ref string) types.Type {
+	var obj types.Object
+	pkgName, name, qualified := strings.Cut(ref, ".")
+	switch {
+	case !qualified:
+		obj = compInfo.Pkg.Types.Scope().Lookup(ref)
+	case pkgName == compInfo.Pkg.Types.Name():
+		obj = compInfo.Pkg.Types.Scope().Lookup(name)
+	default:
+		for _, imp := range compInfo.Pkg.Types.Imports() {
+			if imp.Name() == pkgName {
+				obj = imp.Scope().Lookup(name)
+				break
+			}
+		}
+	}
+
+	if typeName, ok := obj.(*types.TypeName); ok {
+		return typeName.Type()
+	}
+	return types.Typ[types.Invalid]
+}
+
+// bindsTo reports whether the component binds the interface with 'bind='
+func bindsTo(comp *scannedComponent, iface types.Type) bool {
+	return slices.ContainsFunc(comp.Binds, func(b *types.Named) bool { return types.Identical(b, iface) })
+}
+
+// implementsIface reports whether the component implements the interface and did not opt out with 'nobind='
+func implementsIface(comp *scannedComponent, iface types.Type) bool {
+	if slices.ContainsFunc(comp.NoBinds, func(b *types.Named) bool { return types.Identical(b, iface) }) {
+		return false
+	}
+	return types.Implements(comp.PtrType, iface.Underlying().(*types.Interface))
+}
+
+// floraObject looks up a declaration of the flora package the component is
+// marked with. It returns nil if that flora version does not declare it.
+func floraObject(compInfo *componentInfo, name string) types.Object {
+	if compInfo.StructType == nil {
+		for _, imp := range compInfo.Pkg.Types.Imports() {
+			if imp.Path() == FloraPackagePath {
+				return imp.Scope().Lookup(name)
+			}
+		}
+		return nil
+	}
+	for i := 0; i < compInfo.StructType.NumFields(); i++ {
+		field := compInfo.StructType.Field(i)
+		if named, ok := field.Type().(*types.Named); ok && field.Anonymous() && slices.Contains(markers, field.Type().String()) {
+			return named.Obj().Pkg().Scope().Lookup(name)
+		}
+	}
+	return nil
+}
+
+// setLifecycle marks singletons that implement flora.Starter or flora.Stopper.
+// The container holds the value returned by the provider in an addressable
+// field, so pointer methods count as well.
+func setLifecycle(compInfo *componentInfo, metadata *engine.ComponentMetadata, sig *types.Signature) {
+	if metadata.Scope != ScopeSingleton {
+		return
+	}
+
+	implements := func(name string) bool {
+		obj := floraObject(compInfo, name)
+		if obj == nil {
+			return false
+		}
+		iface, ok := obj.Type().Underlying().(*types.Interface)
+		if !ok {
+			return false
+		}
+		retType := sig.Results().At(0).Type()
+		return types.Implements(retType, iface) || types.Implements(types.NewPointer(retType), iface)
+	}
+
+	metadata.IsStarter = implements("Starter")
+	metadata.IsStopper = implements("Stopper")
+}
+
+// lazyTypeOf returns '*flora.Lazy[T]' for a lazy component and nil otherwise.
+// flora.Lazy is looked up in the flora package the component is marked with.
+func lazyTypeOf(compInfo *componentInfo, metadata *engine.ComponentMetadata, sig *types.Signature) (types.Type, error) {
+	if metadata.Scope != ScopeLazy {
+		return nil, nil
+	}
+
+	lazyObj := floraObject(compInfo, "Lazy")
+	if lazyObj == nil {
+		return nil, errs.WrapAt(metadata.Position, ErrInvalidMetadata, "scope 'lazy' of component '%s' in package '%s' requires a flora version that provides flora.Lazy",
+			metadata.StructName, metadata.PackageName)
+	}
+
+	lazy, err := types.Instantiate(nil, lazyObj.Type(), []types.Type{sig.Results().At(0).Type()}, true)
+	if err != nil {
+		chainErr := fmt.Errorf("%w: %w", ErrInvalidMetadata, err)
+		return nil, errs.WrapAt(metadata.Position, chainErr, "cannot create flora.Lazy for component '%s' in package '%s'", metadata.StructName, metadata.PackageName)
+	}
+
+	return types.NewPointer(lazy), nil
+}
+
+// dropConditionalComponents removes every 'conditional=missing' component for
+// which another, unconditional component provides the same type or implements
+// an interface that both of them could be injected as
+func dropConditionalComponents(components []*scannedComponent) []*scannedComponent {
+	neededInterfaces := make(map[string]types.Type)
+	for _, comp := range components {
+		maps.Copy(neededInterfaces, comp.NeededInterfaces)
+	}
+
+	return slices.DeleteFunc(slices.Clone(components), func(comp *scannedComponent) bool {
+		if comp.Metadata.Conditional != ConditionalOnMissing {
+			return false
+		}
+
+		for _, other := range components {
+			if other.Metadata.Conditional == ConditionalOnMissing || !providesSameAs(comp, other, neededInterfaces) {
+				continue
+			}
+
+			log.Debug("Conditional component backs off", "component", comp.Metadata.StructName, "package", comp.Metadata.PackageName,
+				"replaced_by", other.Metadata.StructName, "replaced_by_package", other.Metadata.PackageName)
+			return true
+		}
+
+		return false
+	})
+}
+
+// providesSameAs reports whether two components compete for the same injection point
+func providesSameAs(a, b *scannedComponent, neededInterfaces map[string]types.Type) bool {
+	if a.Metadata.Qualifier != "" || b.Metadata.Qualifier != "" {
+		return a.Metadata.Qualifier == b.Metadata.Qualifier
+	}
+
+	baseA, baseB := a.PtrType.Elem(), b.PtrType.Elem()
+	if types.Identical(baseA, baseB) {
+		return true
+	}
+
+	if iface, ok := baseA.Underlying().(*types.Interface); ok && !iface.Empty() && types.Implements(b.PtrType, iface) {
+		return true
+	}
+	if iface, ok := baseB.Underlying().(*types.Interface); ok && !iface.Empty() && types.Implements(a.PtrType, iface) {
+		return true
+	}
+
+	for _, neededType := range neededInterfaces {
 		iface := neededType.Underlying().(*types.Interface)
+		if types.Implements(a.PtrType, iface) && types.Implements(b.PtrType, iface) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// bindQualifiersToComponents checks that qualifiers are unique and that every
+// qualified parameter can be satisfied by the component with that name
+func bindQualifiersToComponents(components []*scannedComponent, fset *token.FileSet) error {
+	named := make(map[string]*scannedComponent)
+	var bindErrs []error
+
+	for _, comp := range components {
+		name := comp.Metadata.Qualifier
+		if name == "" {
+			continue
+		}
+		if other, exists := named[name]; exists {
+			bindErrs = append(bindErrs, errs.WrapAt(comp.Metadata.Position, ErrInvalidMetadata, "duplicate qualifier '%s': used by component '%s' in package '%s' and component '%s' in package '%s'",
+				name, other.Metadata.StructName, other.Metadata.PackageName, comp.Metadata.StructName, comp.Metadata.PackageName))
+			continue
+		}
+		named[name] = comp
+	}
+
+	for _, comp := range components {
+		if len(comp.Metadata.ParamQualifiers) == 0 {
+			continue
+		}
+
+		for v := range comp.Signature.Params().Variables() {
+			name, qualified := comp.Metadata.ParamQualifiers[v.Name()]
+			if !qualified {
+				continue
+			}
+
+			target, exists := named[name]
+			if !exists {
+				chainErr := fmt.Errorf("%w: %s", ErrUnknownQualifier, name)
+				bindErrs = append(bindErrs, errs.WrapAt(paramPosition(comp, v, fset), chainErr, "parameter '%s' of provider func '%s' for component '%s' requests qualifier '%s', but no component has that name",
+					v.Name(), comp.Metadata.ConstructorName, comp.Metadata.StructName, name))
+				continue
+			}
+
+			if !types.AssignableTo(providedType(target), v.Type()) {
+				chainErr := fmt.Errorf("%w: %v", ErrQualifierMismatch, v.Type())
+				bindErrs = append(bindErrs, errs.WrapAt(paramPosition(comp, v, fset), chainErr, "parameter '%s' of provider func '%s' for component '%s' has type '%s', but component '%s' named '%s' provides '%s'",
+					v.Name(), comp.Metadata.ConstructorName, comp.Metadata.StructName, v.Type().String(), target.Metadata.StructName, name, providedType(target).String()))
+				continue
+			}
+
+			log.Debug("Bound qualified parameter", "qualifier", name, "param", v.Name(), "component", comp.Metadata.StructName)
+		}
+	}
+
+	return errors.Join(bindErrs...)
+}
+
+// bindInterfacesToComponents binds the needed interfaces to the components that implement them.
+// Named components are only injected by qualifier and lazy components only as flora.Lazy,
+// so neither takes part in plain interface binding. Components that 'bind=' an interface
+// take precedence over those implementing it by accident.
+func bindInterfacesToComponents(components []*scannedComponent, neededInterfaces map[string]types.Type, fset *token.FileSet) error {
+	var bindErrs []error
+	for _, neededName := range slices.Sorted(maps.Keys(neededInterfaces)) {
+		neededType := neededInterfaces[neededName]
+		pos := requestPosition(components, fset, func(t types.Type) bool {
+			if sig, isFunc := t.(*types.Signature); isFunc && sig.Results().Len() > 0 {
+				t = sig.Results().At(0).Type()
+			}
+			return types.Identical(t, neededType)
+		})
+		if slices.ContainsFunc(components, func(comp *scannedComponent) bool {
+			return comp.Metadata.Qualifier == "" && comp.Metadata.Scope != ScopeLazy && types.Identical(comp.PtrType.Elem(), neededType)
+		}) {
+			log.Debug("Interface is provided directly, no binding needed", "interface", neededName)
+			continue
+		}
 
 		var implementers []*scannedComponent
 
 		for _, comp := range components {
-			if types.Implements(comp.PtrType, iface) {
+			if comp.Metadata.Qualifier == "" && comp.Metadata.Scope != ScopeLazy && !comp.Shared && implementsIface(comp, neededType) {
 				implementers = append(implementers, comp)
 			}
 		}
+		if explicit := slices.DeleteFunc(slices.Clone(implementers), func(comp *scannedComponent) bool {
+			return !bindsTo(comp, neededType)
+		}); len(explicit) > 0 {
+			implementers = explicit
+		}
 
 		bindToComp := func(comp *scannedComponent, ifaceType types.Type) error {
 			if named, ok := ifaceType.(*types.Named); ok {
@@ -447,12 +1495,14 @@ func bindInterfacesToComponents(components []*scannedComponent, neededInterfaces
 					PackageName:   named.Obj().Pkg().Name(),
 					PackagePath:   named.Obj().Pkg().Path(),
 					InterfaceName: named.Obj().Name(),
+					TypeArgs:      typeArgsMetadata(named.TypeArgs()),
+					Explicit:      bindsTo(comp, ifaceType),
 				})
-				log.Debug("Bound interface to component", "interface", neededName, "component", implementers[0].Metadata.StructName)
+				log.Debug("Bound interface to component", "interface", neededName, "component", comp.Metadata.StructName)
 
 			} else {
 				chainErr := fmt.Errorf("%w: %v", ErrInvalidInterface, ifaceType)
-				return errs.Wrap(chainErr, "cannot bind anonymous interface '%s' to component '%s' in package '%s': only named interfaces are supported",
+				return errs.WrapAt(pos, chainErr, "cannot bind anonymous interface '%s' to component '%s' in package '%s': only named interfaces are supported",
 					ifaceType.String(), comp.Metadata.StructName, comp.Metadata.PackageName)
 			}
 			return nil
@@ -460,7 +1510,7 @@ func bindInterfacesToComponents(components []*scannedComponent, neededInterfaces
 
 		if len(implementers) == 1 {
 			if err := bindToComp(implementers[0], neededType); err != nil {
-				return err
+				bindErrs = append(bindErrs, err)
 			}
 		} else if len(implementers) > 1 {
 			var primaryComp *scannedComponent
@@ -476,34 +1526,35 @@ func bindInterfacesToComponents(components []*scannedComponent, neededInterfaces
 			switch primaryCount {
 			case 1:
 				if err := bindToComp(primaryComp, neededType); err != nil {
-					return err
+					bindErrs = append(bindErrs, err)
 				}
 			case 0:
 				chainErr := fmt.Errorf("%w: %v", ErrInterfaceCollision, implementers)
-				return errs.Wrap(chainErr, "interface collision: %d components implement injected interface '%s', but none is marked 'primary'", len(implementers), neededName)
+				bindErrs = append(bindErrs, errs.WrapAt(pos, chainErr, "interface collision: %d components implement injected interface '%s', but none is marked 'primary'", len(implementers), neededName))
 			default:
 				chainErr := fmt.Errorf("%w: %v", ErrInterfaceCollision, implementers)
-				return errs.Wrap(chainErr, "interface collision: multiple components implementing '%s' are marked as 'primary'", neededName)
+				bindErrs = append(bindErrs, errs.WrapAt(pos, chainErr, "interface collision: multiple components implementing '%s' are marked as 'primary'", neededName))
 			}
 
 		} else {
 			chainErr := fmt.Errorf("%w: %v", ErrNoImplementation, neededName)
-			return errs.Wrap(chainErr, "no component found that implements interface '%s'", neededName)
+			bindErrs = append(bindErrs, errs.WrapAt(pos, chainErr, "no component found that implements interface '%s'%s", neededName, nearMisses(components, neededType)))
 		}
 	}
-	return nil
+	return errors.Join(bindErrs...)
 }
 
 // bindSlicesToComponents binds the needed slices to the components that implement them
-func bindSlicesToComponents(components []*scannedComponent, neededSlices map[string]types.Type) ([]*engine.SliceBindingMetadata, error) {
+func bindSlicesToComponents(components []*scannedComponent, neededSlices map[string]types.Type, fset *token.FileSet) ([]*engine.SliceBindingMetadata, error) {
 	var sliceBindings []*engine.SliceBindingMetadata
+	var bindErrs []error
 
-	for neededName, neededType := range neededSlices {
-		iface := neededType.Underlying().(*types.Interface)
+	for _, neededName := range slices.Sorted(maps.Keys(neededSlices)) {
+		neededType := neededSlices[neededName]
 		var implementers []*engine.ComponentMetadata
 
 		for _, comp := range components {
-			if types.Implements(comp.PtrType, iface) {
+			if isCollectable(comp) && implementsIface(comp, neededType) {
 				implementers = append(implementers, comp.Metadata)
 			}
 		}
@@ -514,34 +1565,114 @@ func bindSlicesToComponents(components []*scannedComponent, neededSlices map[str
 					PackageName:   named.Obj().Pkg().Name(),
 					PackagePath:   named.Obj().Pkg().Path(),
 					InterfaceName: named.Obj().Name(),
+					TypeArgs:      typeArgsMetadata(named.TypeArgs()),
 				},
 				Implementations: implementers,
+				Position:        fset.Position(named.Obj().Pos()),
 			})
 			log.Debug("Resolved slice binding", "interface", neededName, "implementations_count", len(implementers))
 		} else {
 			chainErr := fmt.Errorf("%w: %v", ErrInvalidSlice, neededType)
-			return nil, errs.Wrap(chainErr, "cannot bind anonymous slice '%s': only named slices and interfaces are supported", neededType.String())
+			pos := requestPosition(components, fset, func(t types.Type) bool {
+				slice, isSlice := t.(*types.Slice)
+				return isSlice && types.Identical(slice.Elem(), neededType)
+			})
+			bindErrs = append(bindErrs, errs.WrapAt(pos, chainErr, "cannot bind anonymous slice '%s': only named slices and interfaces are supported", neededType.String()))
 		}
 	}
+	if len(bindErrs) > 0 {
+		return nil, errors.Join(bindErrs...)
+	}
 	return sliceBindings, nil
 }
 
-// validateReturnValues validates the return values of a provider function
+// defaultKey keys a component without 'key=' in maps by its struct name,
+// instantiations of generic components with their type arguments, e.g. "Repo[main.User]"
+func defaultKey(comp *engine.ComponentMetadata) string {
+	if len(comp.TypeArgs) == 0 {
+		return comp.StructName
+	}
+	args := make([]string, 0, len(comp.TypeArgs))
+	for _, arg := range comp.TypeArgs {
+		args = append(args, arg.Type)
+	}
+	return comp.StructName + "[" + strings.Join(args, ", ") + "]"
+}
+
+// bindMapsToComponents binds the needed maps to the components that implement them.
+// Every implementation is keyed by its 'key=' tag, defaulting to defaultKey.
+func bindMapsToComponents(components []*scannedComponent, neededMaps map[string]types.Type, fset *token.FileSet) ([]*engine.MapBindingMetadata, error) {
+	var mapBindings []*engine.MapBindingMetadata
+	var bindErrs []error
+
+	for _, neededName := range slices.Sorted(maps.Keys(neededMaps)) {
+		neededType := neededMaps[neededName]
+		named, ok := neededType.(*types.Named)
+		if !ok {
+			chainErr := fmt.Errorf("%w: %v", ErrInvalidMap, neededType)
+			pos := requestPosition(components, fset, func(t types.Type) bool {
+				mapType, isMap := t.(*types.Map)
+				return isMap && types.Identical(mapType.Elem(), neededType)
+			})
+			bindErrs = append(bindErrs, errs.WrapAt(pos, chainErr, "cannot bind anonymous map value '%s': only named interfaces are supported", neededType.String()))
+			continue
+		}
+
+		var implementers []*engine.ComponentMetadata
+		keys := make(map[string]*engine.ComponentMetadata)
+
+		for _, comp := range components {
+			if !isCollectable(comp) || !implementsIface(comp, neededType) {
+				continue
+			}
+
+			if comp.Metadata.Key == "" {
+				comp.Metadata.Key = defaultKey(comp.Metadata)
+			}
+			if other, exists := keys[comp.Metadata.Key]; exists {
+				chainErr := fmt.Errorf("%w: %s", ErrDuplicateKey, comp.Metadata.Key)
+				bindErrs = append(bindErrs, errs.WrapAt(comp.Metadata.Position, chainErr, "components '%s' in package '%s' and '%s' in package '%s' both use key '%s' in 'map[string]%s' (set a unique 'key=')",
+					other.StructName, other.PackageName, comp.Metadata.StructName, comp.Metadata.PackageName, comp.Metadata.Key, neededName))
+				continue
+			}
+			keys[comp.Metadata.Key] = comp.Metadata
+			implementers = append(implementers, comp.Metadata)
+		}
+
+		mapBindings = append(mapBindings, &engine.MapBindingMetadata{
+			Interface: engine.InterfaceMetadata{
+				PackageName:   named.Obj().Pkg().Name(),
+				PackagePath:   named.Obj().Pkg().Path(),
+				InterfaceName: named.Obj().Name(),
+				TypeArgs:      typeArgsMetadata(named.TypeArgs()),
+			},
+			Implementations: implementers,
+			Position:        fset.Position(named.Obj().Pos()),
+		})
+		log.Debug("Resolved map binding", "interface", neededName, "implementations_count", len(implementers))
+	}
+	if len(bindErrs) > 0 {
+		return nil, errors.Join(bindErrs...)
+	}
+	return mapBindings, nil
+}
+
+// validateReturnValues validates the return values of a provider function declared at pos
 // Returns: (hasCleanup, hasError, error)
-func validateReturnValues(sig *types.Signature, constructorName, structName, pkgName string) (bool, bool, error) {
+func validateReturnValues(sig *types.Signature, pos token.Position, constructorName, structName, pkgName string) (bool, bool, error) {
 	results := sig.Results()
 	numResults := results.Len()
 
 	if numResults == 0 || numResults > 3 {
 		chainErr := fmt.Errorf("%w: %v", ErrInvalidProviderFunc, results)
-		return false, false, errs.Wrap(chainErr, "invalid provider func: '%s' for component '%s' in package '%s' must return 1, 2, or 3 values",
+		return false, false, errs.WrapAt(pos, chainErr, "invalid provider func: '%s' for component '%s' in package '%s' must return 1, 2, or 3 values",
 			constructorName, structName, pkgName)
 	}
 
 	firstType := results.At(0).Type()
 	if firstType.String() == "error" || isCleanupFunc(firstType) {
 		chainErr := fmt.Errorf("%w: %v", ErrInvalidProviderFunc, firstType)
-		return false, false, errs.Wrap(chainErr, "invalid provider func: '%s' for component '%s': 1st return value must neither be 'error' nor 'func()'", constructorName, structName)
+		return false, false, errs.WrapAt(pos, chainErr, "invalid provider func: '%s' for component '%s': 1st return value must neither be 'error' nor 'func()'", constructorName, structName)
 	}
 
 	hasCleanup, hasErr := false, false
@@ -552,7 +1683,7 @@ func validateReturnValues(sig *types.Signature, constructorName, structName, pkg
 		hasCleanup = isCleanupFunc(secondType)
 		if !hasErr && !hasCleanup {
 			chainErr := fmt.Errorf("%w: %v", ErrInvalidProviderFunc, secondType)
-			return false, false, errs.Wrap(chainErr, "invalid provider func: '%s' for component '%s': 2nd return value must be 'error' or 'func()'", constructorName, structName)
+			return false, false, errs.WrapAt(pos, chainErr, "invalid provider func: '%s' for component '%s': 2nd return value must be 'error' or 'func()'", constructorName, structName)
 		}
 	}
 
@@ -560,12 +1691,12 @@ func validateReturnValues(sig *types.Signature, constructorName, structName, pkg
 		secondType := results.At(1).Type()
 		if !isCleanupFunc(secondType) {
 			chainErr := fmt.Errorf("%w: %v", ErrInvalidProviderFunc, secondType)
-			return false, false, errs.Wrap(chainErr, "invalid provider func: '%s' for component '%s': 2nd return value must be 'func()' when returning 3 values", constructorName, structName)
+			return false, false, errs.WrapAt(pos, chainErr, "invalid provider func: '%s' for component '%s': 2nd return value must be 'func()' when returning 3 values", constructorName, structName)
 		}
 		thirdType := results.At(2).Type()
 		if thirdType.String() != "error" {
 			chainErr := fmt.Errorf("%w: %v", ErrInvalidProviderFunc, thirdType)
-			return false, false, errs.Wrap(chainErr, "invalid provider func: '%s' for component '%s': 3rd return value must be 'error' when returning 3 values", constructorName, structName)
+			return false, false, errs.WrapAt(pos, chainErr, "invalid provider func: '%s' for component '%s': 3rd return value must be 'error' when returning 3 values", constructorName, structName)
 		}
 		hasCleanup, hasErr = true, true
 	}
@@ -573,9 +1704,78 @@ func validateReturnValues(sig *types.Signature, constructorName, structName, pkg
 	return hasCleanup, hasErr, nil
 }
 
-// processConfiguration scans a flora.Configuration struct for methods with magic comments
-func processConfiguration(compInfo *componentInfo, neededInterfaces, neededSlices *map[string]types.Type) ([]*scannedComponent, error) {
+// configInstance processes the constructor of a Configuration that has one, a 'New<Struct>'
+// function, a 'constructor=' option on the marker or injected fields. Its methods are then called
+// on the one instance it builds. It reports false if the Configuration is not active in the profile.
+func configInstance(compInfo *componentInfo, profile string) (*scannedComponent, bool, error) {
+	metadata := &engine.ComponentMetadata{StructName: compInfo.Name, PackageName: compInfo.Pkg.Name}
+	if err := parseFloraTag(compInfo.Tag, compInfo.Pkg.Fset.Position(compInfo.TagPos), metadata); err != nil {
+		return nil, false, err
+	}
+	if !isActiveInProfile(metadata, profile) {
+		log.Debug("Skipping configuration outside of active profile", "configuration", compInfo.Name, "profiles", metadata.Profiles)
+		return nil, false, nil
+	}
+
+	hasConstructor := floraTagKeys(compInfo.Tag)["constructor"] ||
+		compInfo.Pkg.Types.Scope().Lookup("New"+compInfo.Name) != nil
+	// Only fields set by field injection need the instance, invalid tags are left to injectionConstructor
+	for i := 0; i < compInfo.StructType.NumFields(); i++ {
+		field := compInfo.StructType.Field(i)
+		if field.Embedded() && slices.Contains(markers, field.Type().String()) {
+			continue
+		}
+		switch tag := reflect.StructTag(compInfo.StructType.Tag(i)).Get("flora"); tag {
+		case "-":
+		case "":
+			if metadata.Autowire && field.Exported() && !field.Embedded() {
+				hasConstructor = true
+			}
+		default:
+			hasConstructor = true
+		}
+	}
+	if !hasConstructor {
+		return nil, true, nil
+	}
+
+	instance, err := processComponent(compInfo, profile)
+	if err != nil || instance == nil {
+		return nil, false, err
+	}
+
+	if instance.Metadata.Scope != ScopeSingleton || instance.Metadata.Qualifier != "" {
+		return nil, false, errs.WrapAt(instance.Metadata.Position, ErrInvalidMetadata, "configuration '%s' in package '%s' is shared by its methods and must be an unnamed singleton",
+			compInfo.Name, compInfo.Pkg.Name)
+	}
+	instance.Metadata.Hidden = true
+	instance.Shared = true
+
+	return instance, true, nil
+}
+
+// withReceiver turns a Configuration method into a function taking the shared instance as first parameter
+func withReceiver(method *types.Func, recvType types.Type) *types.Func {
+	sig := method.Type().(*types.Signature)
+	params := []*types.Var{types.NewParam(token.NoPos, method.Pkg(), "", recvType)}
+	params = append(params, slices.Collect(sig.Params().Variables())...)
+	return types.NewFunc(method.Pos(), method.Pkg(), method.Name(),
+		types.NewSignatureType(nil, nil, nil, types.NewTuple(params...), sig.Results(), sig.Variadic()))
+}
+
+// processConfiguration scans a flora.Configuration struct for methods with magic comments.
+// Every method is processed, the errors of the invalid ones are joined.
+func processConfiguration(compInfo *componentInfo, profile string) ([]*scannedComponent, error) {
 	var results []*scannedComponent
+	var errList []error
+
+	instance, active, err := configInstance(compInfo, profile)
+	if err != nil || !active {
+		return nil, err
+	}
+	if instance != nil {
+		results = append(results, instance)
+	}
 
 	for _, file := range compInfo.Pkg.Syntax {
 		for _, decl := range file.Decls {
@@ -584,18 +1784,7 @@ func processConfiguration(compInfo *componentInfo, neededInterfaces, neededSlice
 				continue
 			}
 
-			recvType := funcDecl.Recv.List[0].Type
-			var recvName string
-			switch t := recvType.(type) {
-			case *ast.Ident:
-				recvName = t.Name
-			case *ast.StarExpr:
-				if ident, ok := t.X.(*ast.Ident); ok {
-					recvName = ident.Name
-				}
-			}
-
-			if recvName != compInfo.Name {
+			if receiverName(funcDecl) != compInfo.Name {
 				continue
 			}
 
@@ -605,25 +1794,20 @@ func processConfiguration(compInfo *componentInfo, neededInterfaces, neededSlice
 				continue
 			}
 
-			var floraTag string
-			if funcDecl.Doc != nil {
-				for _, comment := range funcDecl.Doc.List {
-					text := strings.TrimSpace(comment.Text)
-					if after, ok0 := strings.CutPrefix(text, "// flora:"); ok0 {
-						floraTag = strings.TrimSpace(after)
-						break
-					}
-				}
-			}
+			floraTag, floraTagPos := floraDirective(funcDecl.Doc)
 
 			obj := compInfo.Pkg.TypesInfo.Defs[funcDecl.Name]
 
+			// StructName and PackageName name the method in errors until the provided type is known
 			metadata := &engine.ComponentMetadata{
+				StructName:        compInfo.Name + "." + methodName,
+				PackageName:       compInfo.Pkg.Name,
 				ConfigStructName:  compInfo.Name,
 				ConfigMethodName:  methodName,
 				ConfigPackageName: compInfo.Pkg.Name,
 				ConfigPackagePath: compInfo.Pkg.PkgPath,
 				ConstructorName:   fmt.Sprintf("Provide_%s_%s", compInfo.Name, methodName),
+				Position:          compInfo.Pkg.Fset.Position(funcDecl.Name.Pos()),
 			}
 
 			var tagToParse string
@@ -631,41 +1815,132 @@ func processConfiguration(compInfo *componentInfo, neededInterfaces, neededSlice
 				tagToParse = fmt.Sprintf(`flora:"%s"`, floraTag)
 			}
 
-			if err := parseFloraTag(tagToParse, metadata); err != nil {
-				return nil, err
+			if err := parseFloraTag(tagToParse, compInfo.Pkg.Fset.Position(floraTagPos), metadata); err != nil {
+				errList = append(errList, err)
+				continue
 			}
 
-			if err := processProviderFunc(compInfo, metadata, obj, neededInterfaces, neededSlices); err != nil {
-				return nil, err
+			if !isActiveInProfile(metadata, profile) {
+				log.Debug("Skipping configuration method outside of active profile", "method", methodName, "profiles", metadata.Profiles)
+				continue
 			}
 
-			sig := obj.(*types.Func).Type().(*types.Signature)
-			retType := sig.Results().At(0).Type()
-			var ptrType *types.Pointer
-			if ptr, isPtr := retType.(*types.Pointer); isPtr {
-				ptrType = ptr
-			} else {
-				ptrType = types.NewPointer(retType)
+			if method, ok := obj.(*types.Func); ok && instance != nil {
+				metadata.ConfigInstance = true
+				obj = withReceiver(method, instance.Signature.Results().At(0).Type())
 			}
 
-			results = append(results, &scannedComponent{
-				Metadata: metadata,
-				PtrType:  ptrType,
-			})
+			scannedComp, err := processProvidedType(compInfo, metadata, obj)
+			if err != nil {
+				errList = append(errList, err)
+				continue
+			}
+			results = append(results, scannedComp)
 		}
 	}
 
+	if len(errList) > 0 {
+		return nil, errors.Join(errList...)
+	}
 	return results, nil
 }
 
+// processProvider processes a package-level function marked with '// flora:provider'.
+// It returns nil if the provider is not active in the given profile.
+func processProvider(compInfo *componentInfo, profile string) (*scannedComponent, error) {
+	metadata := &engine.ComponentMetadata{
+		StructName:          compInfo.Name,
+		PackageName:         compInfo.Pkg.Name,
+		PackagePath:         compInfo.Pkg.PkgPath,
+		ProviderPackageName: compInfo.Pkg.Name,
+		ProviderPackagePath: compInfo.Pkg.PkgPath,
+		Position:            compInfo.Pkg.Fset.Position(compInfo.Pos),
+	}
+
+	var tagToParse string
+	if compInfo.Tag != "" {
+		tagToParse = fmt.Sprintf(`flora:"%s"`, compInfo.Tag)
+	}
+	tagPos := compInfo.Pkg.Fset.Position(compInfo.TagPos)
+	if err := parseFloraTag(tagToParse, tagPos, metadata); err != nil {
+		return nil, err
+	}
+	if metadata.ConstructorName != "New"+compInfo.Name {
+		return nil, errs.WrapAt(tagPos, ErrInvalidMetadata, "provider func '%s' in package '%s' cannot set a constructor", compInfo.Name, compInfo.Pkg.Name)
+	}
+	metadata.ConstructorName = compInfo.Name
+
+	if !ast.IsExported(compInfo.Name) {
+		chainErr := fmt.Errorf("%w: %s", ErrInvalidProviderFunc, compInfo.Name)
+		return nil, errs.WrapAt(metadata.Position, chainErr, "provider func '%s' in package '%s' must be exported", compInfo.Name, compInfo.Pkg.Name)
+	}
+
+	if !isActiveInProfile(metadata, profile) {
+		log.Debug("Skipping provider func outside of active profile", "func", compInfo.Name, "profiles", metadata.Profiles)
+		return nil, nil
+	}
+
+	return processProvidedType(compInfo, metadata, compInfo.Pkg.Types.Scope().Lookup(compInfo.Name))
+}
+
+// processProvidedType scans a provider whose component type is taken from its
+// return value, a Configuration method or a provider func
+func processProvidedType(compInfo *componentInfo, metadata *engine.ComponentMetadata, obj types.Object) (*scannedComponent, error) {
+	neededInterfaces := make(map[string]types.Type)
+	neededSlices := make(map[string]types.Type)
+	neededMaps := make(map[string]types.Type)
+	sig, err := processProviderFunc(compInfo, metadata, obj, &neededInterfaces, &neededSlices, &neededMaps)
+	if err != nil {
+		return nil, err
+	}
+
+	lazyType, err := lazyTypeOf(compInfo, metadata, sig)
+	if err != nil {
+		return nil, err
+	}
+
+	setLifecycle(compInfo, metadata, sig)
+
+	retType := sig.Results().At(0).Type()
+	var ptrType *types.Pointer
+	if ptr, isPtr := retType.(*types.Pointer); isPtr {
+		ptrType = ptr
+	} else {
+		ptrType = types.NewPointer(retType)
+	}
+
+	binds, noBinds, err := resolveBindings(compInfo, metadata, ptrType)
+	if err != nil {
+		return nil, err
+	}
+
+	decorates, err := resolveDecorates(compInfo, metadata, sig)
+	if err != nil {
+		return nil, err
+	}
+
+	return &scannedComponent{
+		Metadata:         metadata,
+		PtrType:          ptrType,
+		Signature:        sig,
+		LazyType:         lazyType,
+		Binds:            binds,
+		NoBinds:          noBinds,
+		Decorates:        decorates,
+		NeededInterfaces: neededInterfaces,
+		NeededSlices:     neededSlices,
+		NeededMaps:       neededMaps,
+	}, nil
+}
+
 func isExported(metadata *engine.ComponentMetadata) error {
-	if len(metadata.ConstructorName) > 0 {
+	if token.IsIdentifier(metadata.ConstructorName) {
 		r := []rune(metadata.ConstructorName)
 		if !unicode.IsUpper(r[0]) {
-			return errs.Wrap(ErrInvalidMetadata, "unexported constructor '%s' for component '%s' in package '%s'", metadata.ConstructorName, metadata.StructName, metadata.PackageName)
+			return errs.WrapAt(metadata.Position, ErrInvalidMetadata, "unexported constructor '%s' for component '%s' in package '%s'", metadata.ConstructorName, metadata.StructName, metadata.PackageName)
 		}
 	} else {
-		return errs.Wrap(ErrInvalidMetadata, "invalid constructor '%s' for component '%s' in package '%s'", metadata.ConstructorName, metadata.StructName, metadata.PackageName)
+		return errs.WrapAt(metadata.Position, ErrInvalidMetadata, "invalid constructor '%s' for component '%s' in package '%s'", metadata.ConstructorName, metadata.StructName, metadata.PackageName)
 	}
 
 	return nil
@@ -17,15 +17,23 @@ package scanner
 
 import (
 	"errors"
+	"fmt"
+	"go/token"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
 	"github.com/soner3/flora/internal/engine"
+	"github.com/soner3/flora/internal/errs"
 )
 
 func TestParsePackages(t *testing.T) {
 	testcases := []struct {
 		name         string
 		testdataPath string
+		opts         Options
 		expErr       error
 	}{
 		{
@@ -149,8 +157,28 @@ func TestParsePackages(t *testing.T) {
 			expErr:       ErrInvalidMetadata,
 		},
 		{
-			name:         "TestParsePackagesUnexportedPositional",
+			name:         "TestParsePackagesPositionalConstructor",
 			testdataPath: "testdata/err_unexported_pos",
+			expErr:       ErrUnknownOption,
+		},
+		{
+			name:         "TestParsePackagesUnknownOption",
+			testdataPath: "testdata/err_unknown_option",
+			expErr:       ErrUnknownOption,
+		},
+		{
+			name:         "TestParsePackagesDirectiveUnknownOption",
+			testdataPath: "testdata/err_directive_unknown_option",
+			expErr:       ErrUnknownOption,
+		},
+		{
+			name:         "TestParsePackagesDuplicateOption",
+			testdataPath: "testdata/err_duplicate_option",
+			expErr:       ErrInvalidMetadata,
+		},
+		{
+			name:         "TestParsePackagesOptionValue",
+			testdataPath: "testdata/err_option_value",
 			expErr:       ErrInvalidMetadata,
 		},
 		{
@@ -163,16 +191,246 @@ func TestParsePackages(t *testing.T) {
 			testdataPath: "testdata/happy",
 			expErr:       nil,
 		},
+		{
+			name:         "TestParsePackagesHappyProfile",
+			testdataPath: "testdata/happy_profile",
+			opts:         Options{Profile: "dev"},
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesProfileFiltersAll",
+			testdataPath: "testdata/happy_profile",
+			opts:         Options{},
+			expErr:       ErrNoImplementation,
+		},
+		{
+			name:         "TestParsePackagesInvalidProfile",
+			testdataPath: "testdata/err_invalid_profile",
+			expErr:       ErrInvalidMetadata,
+		},
+		{
+			name:         "TestParsePackagesHappyBuildTags",
+			testdataPath: "testdata/happy_tags",
+			opts:         Options{Tags: []string{"integration"}, GOOS: "windows"},
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesBuildTagsHideComponents",
+			testdataPath: "testdata/happy_tags",
+			opts:         Options{GOOS: "linux"},
+			expErr:       ErrNoImplementation,
+		},
+		{
+			name:         "TestParsePackagesHappyConditional",
+			testdataPath: "testdata/happy_conditional",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesInvalidConditional",
+			testdataPath: "testdata/err_invalid_conditional",
+			expErr:       ErrInvalidMetadata,
+		},
+		{
+			name:         "TestParsePackagesHappyGenerics",
+			testdataPath: "testdata/happy_generics",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesGenericConstructorMismatch",
+			testdataPath: "testdata/err_generic_constructor",
+			expErr:       ErrInvalidGeneric,
+		},
+		{
+			name:         "TestParsePackagesHappyMap",
+			testdataPath: "testdata/happy_map",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesDuplicateMapKey",
+			testdataPath: "testdata/err_duplicate_key",
+			expErr:       ErrDuplicateKey,
+		},
+		{
+			name:         "TestParsePackagesInvalidMapKey",
+			testdataPath: "testdata/err_invalid_map",
+			expErr:       ErrInvalidMap,
+		},
+		{
+			name:         "TestParsePackagesHappyLazy",
+			testdataPath: "testdata/happy_lazy",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesLazyNotBoundToInterface",
+			testdataPath: "testdata/err_lazy_interface",
+			expErr:       ErrNoImplementation,
+		},
+		{
+			name:         "TestParsePackagesHappyRequest",
+			testdataPath: "testdata/happy_request",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesScopeMismatch",
+			testdataPath: "testdata/err_scope_mismatch",
+			expErr:       ErrScopeMismatch,
+		},
+		{
+			name:         "TestParsePackagesHappyContext",
+			testdataPath: "testdata/happy_context",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesContextLazy",
+			testdataPath: "testdata/err_context_lazy",
+			expErr:       ErrInvalidProviderFunc,
+		},
+		{
+			name:         "TestParsePackagesHappyBind",
+			testdataPath: "testdata/happy_bind",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesBindNotImplemented",
+			testdataPath: "testdata/err_bind_not_implemented",
+			expErr:       ErrInvalidInterface,
+		},
+		{
+			name:         "TestParsePackagesHappyInject",
+			testdataPath: "testdata/happy_inject",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesInjectUnexported",
+			testdataPath: "testdata/err_inject_unexported",
+			expErr:       ErrInvalidMetadata,
+		},
+		{
+			name:         "TestParsePackagesInjectWithConstructor",
+			testdataPath: "testdata/err_inject_constructor",
+			expErr:       ErrInvalidProviderFunc,
+		},
+		{
+			name:         "TestParsePackagesHappyConfigInstance",
+			testdataPath: "testdata/happy_config_instance",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesConfigInstanceScope",
+			testdataPath: "testdata/err_config_instance_scope",
+			expErr:       ErrInvalidMetadata,
+		},
+		{
+			name:         "TestParsePackagesConfigMarkerTag",
+			testdataPath: "testdata/happy_config_marker_tag",
+			opts:         Options{Profile: "dev"},
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesHappyConstructorDirective",
+			testdataPath: "testdata/happy_constructor_directive",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesDirectiveConflict",
+			testdataPath: "testdata/err_directive_conflict",
+			expErr:       ErrConflictingMetadata,
+		},
+		{
+			name:         "TestParsePackagesDirectiveConflictDefault",
+			testdataPath: "testdata/err_directive_conflict_default",
+			expErr:       ErrConflictingMetadata,
+		},
+		{
+			name:         "TestParsePackagesHappyProvider",
+			testdataPath: "testdata/happy_provider",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesProviderUnexported",
+			testdataPath: "testdata/err_provider_unexported",
+			expErr:       ErrInvalidProviderFunc,
+		},
+		{
+			name:         "TestParsePackagesHappyDecorator",
+			testdataPath: "testdata/happy_decorator",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesDecoratorWithoutNext",
+			testdataPath: "testdata/err_decorator_param",
+			expErr:       ErrInvalidDecorator,
+		},
+		{
+			name:         "TestParsePackagesDecoratorWithoutTarget",
+			testdataPath: "testdata/err_decorator_target",
+			expErr:       ErrInvalidDecorator,
+		},
+		{
+			name:         "TestParsePackagesHappyModules",
+			testdataPath: "testdata/happy_modules",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesRequestScopedModuleRoot",
+			testdataPath: "testdata/err_module_request",
+			expErr:       ErrInvalidMetadata,
+		},
+		{
+			name:         "TestParsePackagesInvalidModule",
+			testdataPath: "testdata/err_invalid_module",
+			expErr:       ErrInvalidMetadata,
+		},
+		{
+			name:         "TestParsePackagesHappyExpose",
+			testdataPath: "testdata/happy_expose",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesExposedAndInternal",
+			testdataPath: "testdata/err_expose_internal",
+			expErr:       ErrInvalidMetadata,
+		},
+		{
+			name:         "TestParsePackagesDependencyCycle",
+			testdataPath: "testdata/err_cycle",
+			expErr:       ErrDependencyCycle,
+		},
+		{
+			name:         "TestParsePackagesHappyNamed",
+			testdataPath: "testdata/happy_named",
+			expErr:       nil,
+		},
+		{
+			name:         "TestParsePackagesUnknownQualifier",
+			testdataPath: "testdata/err_unknown_qualifier",
+			expErr:       ErrUnknownQualifier,
+		},
+		{
+			name:         "TestParsePackagesQualifierMismatch",
+			testdataPath: "testdata/err_qualifier_mismatch",
+			expErr:       ErrQualifierMismatch,
+		},
+		{
+			name:         "TestParsePackagesDuplicateName",
+			testdataPath: "testdata/err_duplicate_name",
+			expErr:       ErrInvalidMetadata,
+		},
+		{
+			name:         "TestParsePackagesQualifierUnknownParam",
+			testdataPath: "testdata/err_qualifier_param",
+			expErr:       ErrInvalidMetadata,
+		},
 	}
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			packages, err := ScanPackages(tc.testdataPath)
+			packages, err := ScanPackages(tc.testdataPath, tc.opts)
 			if err != nil {
 				t.Fatalf("ScanPackages failed: %v", err)
 			}
 
-			genCtx, err := ParsePackages(packages)
+			genCtx, err := ParsePackages(packages, tc.opts)
 
 			if tc.expErr != nil {
 				if !errors.Is(err, tc.expErr) {
@@ -222,3 +480,602 @@ func TestIsExported(t *testing.T) {
 	}
 
 }
+
+func TestParseFloraTag(t *testing.T) {
+	testcases := []struct {
+		name    string
+		tag     string
+		expErr  error
+		expHint string
+	}{
+		{name: "TestRepeatedQualifier", tag: `flora:"qualifier=a:x,qualifier=b:y,bind=Reader,bind=Writer"`},
+		{name: "TestTypo", tag: `flora:"primry"`, expErr: ErrUnknownOption, expHint: "did you mean 'primary'?"},
+		{name: "TestKeyTypo", tag: `flora:"scop=prototype"`, expErr: ErrUnknownOption, expHint: "did you mean 'scope'?"},
+		{name: "TestPositionalConstructor", tag: `flora:"NewCache,primary"`},
+		{name: "TestPositionalAndNamedConstructor", tag: `flora:"NewCache,constructor=BuildCache"`, expErr: ErrInvalidMetadata},
+		{name: "TestScopeTypo", tag: `flora:"scope=protoype"`, expErr: ErrInvalidMetadata, expHint: "did you mean 'prototype'?"},
+		{name: "TestInvalidConstructor", tag: `flora:"constructor=New-Cache"`, expErr: ErrInvalidMetadata},
+		{name: "TestMissingValue", tag: `flora:"scope"`, expErr: ErrInvalidMetadata},
+		{name: "TestDuplicate", tag: `flora:"primary,primary"`, expErr: ErrInvalidMetadata},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			metadata := &engine.ComponentMetadata{StructName: "Cache", PackageName: "main"}
+			err := parseFloraTag(tc.tag, token.Position{}, metadata)
+			if !errors.Is(err, tc.expErr) || (tc.expErr == nil && err != nil) {
+				t.Fatalf("expected error %v, got %v", tc.expErr, err)
+			}
+			if tc.expHint != "" && !strings.Contains(err.Error(), tc.expHint) {
+				t.Errorf("expected %q in %q", tc.expHint, err.Error())
+			}
+		})
+	}
+}
+
+func TestDeprecatedTags(t *testing.T) {
+	packages, err := ScanPackages("testdata/happy", Options{})
+	if err != nil {
+		t.Fatalf("ScanPackages failed: %v", err)
+	}
+
+	warnings := deprecatedTags(*parseMarkedComponents(packages))
+	if len(warnings) != 1 {
+		t.Fatalf("expected one warning for the positional constructor of C, got %v", warnings)
+	}
+	var floraErr *errs.FloraError
+	if !errors.Is(warnings[0], ErrDeprecatedOption) || !errors.As(warnings[0], &floraErr) || floraErr.Position.Line != 36 {
+		t.Errorf("expected ErrDeprecatedOption at line 36, got %v", warnings[0])
+	}
+	if !strings.Contains(warnings[0].Error(), "use 'constructor=NewC'") {
+		t.Errorf("expected a migration hint, got %v", warnings[0])
+	}
+}
+
+func TestIsActiveInProfile(t *testing.T) {
+	testcases := []struct {
+		name     string
+		profiles []string
+		profile  string
+		expected bool
+	}{
+		{name: "TestNoProfile", profiles: nil, profile: "dev", expected: true},
+		{name: "TestNoProfileNoneActive", profiles: nil, profile: "", expected: true},
+		{name: "TestMatchingProfile", profiles: []string{"dev", "test"}, profile: "test", expected: true},
+		{name: "TestOtherProfile", profiles: []string{"dev"}, profile: "prod", expected: false},
+		{name: "TestProfileNoneActive", profiles: []string{"dev"}, profile: "", expected: false},
+		{name: "TestNegatedProfile", profiles: []string{"!prod"}, profile: "dev", expected: true},
+		{name: "TestNegatedProfileExcluded", profiles: []string{"!prod"}, profile: "prod", expected: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			metadata := &engine.ComponentMetadata{Profiles: tc.profiles}
+			if got := isActiveInProfile(metadata, tc.profile); got != tc.expected {
+				t.Errorf("expected %v, got %v", tc.expected, got)
+			}
+		})
+	}
+}
+
+func TestParsePackagesConditionalBacksOff(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_conditional", Options{})
+
+	var providers []string
+	for _, comp := range genCtx.Components {
+		providers = append(providers, comp.ConstructorName)
+	}
+
+	for _, dropped := range []string{"NewNoopTracer", "Provide_DefaultsConfig_ProvideLogger"} {
+		if slices.Contains(providers, dropped) {
+			t.Errorf("expected conditional provider %s to back off, got %v", dropped, providers)
+		}
+	}
+
+	for _, kept := range []string{"NewAppTracer", "Provide_AppConfig_ProvideLogger", "Provide_DefaultsConfig_ProvideClock"} {
+		if !slices.Contains(providers, kept) {
+			t.Errorf("expected provider %s to be registered, got %v", kept, providers)
+		}
+	}
+}
+
+func TestParsePackagesGenericInstances(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_generics", Options{})
+
+	instances := make(map[string]*engine.ComponentMetadata)
+	for _, comp := range genCtx.Components {
+		if len(comp.TypeArgs) > 0 {
+			instances[comp.StructName+"["+comp.TypeArgs[0].Type+"]"] = comp
+		}
+	}
+
+	for _, exp := range []string{"Service[main.User]", "Repository[main.User]", "Repository[main.Order]"} {
+		if _, ok := instances[exp]; !ok {
+			t.Errorf("expected instantiation %s, got %v", exp, slices.Collect(maps.Keys(instances)))
+		}
+	}
+
+	if _, ok := instances["Repository[main.Invoice]"]; ok {
+		t.Errorf("expected Store[Invoice] to be bound to InvoiceStore, not to a Repository instantiation")
+	}
+
+	orders := instances["Repository[main.Order]"]
+	if orders == nil || len(orders.Implements) != 1 || orders.Implements[0].InterfaceName != "Store" || orders.Implements[0].TypeArgs[0].Type != "main.Order" {
+		t.Errorf("expected Repository[Order] to be bound to Store[Order], got %+v", orders)
+	}
+
+	if len(genCtx.MapBindings) != 1 {
+		t.Fatalf("expected one map binding, got %d", len(genCtx.MapBindings))
+	}
+	var keys []string
+	for _, impl := range genCtx.MapBindings[0].Implementations {
+		keys = append(keys, impl.Key)
+	}
+	if !slices.Equal(keys, []string{"Repository[main.Order]", "Repository[main.User]"}) {
+		t.Errorf("expected the instantiations to be keyed with their type arguments, got %v", keys)
+	}
+}
+
+func TestParsePackagesExplicitBindings(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_bind", Options{})
+
+	bound := make(map[string][]engine.InterfaceMetadata)
+	for _, comp := range genCtx.Components {
+		bound[comp.StructName] = comp.Implements
+	}
+
+	if len(bound["Router"]) != 1 || bound["Router"][0].InterfaceName != "Handler" || !bound["Router"][0].Explicit {
+		t.Errorf("expected Router to be explicitly bound to http.Handler, got %v", bound["Router"])
+	}
+	if len(bound["MailSender"]) != 1 || bound["MailSender"][0].InterfaceName != "Sender" {
+		t.Errorf("expected MailSender to be bound to Sender, got %v", bound["MailSender"])
+	}
+	if len(bound["AuditLog"]) != 0 {
+		t.Errorf("expected AuditLog to stay unbound, got %v", bound["AuditLog"])
+	}
+}
+
+func TestParsePackagesFieldInjection(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_inject", Options{})
+
+	expected := map[string][]string{
+		"UserService":  {"Repo", "Log"},
+		"OrderService": {"Repo"},
+		"AuditService": {"Logger"},
+	}
+	for _, comp := range genCtx.Components {
+		fields, ok := expected[comp.StructName]
+		if !ok {
+			continue
+		}
+		if !slices.Equal(comp.InjectFields, fields) {
+			t.Errorf("expected %s to inject %v, got %v", comp.StructName, fields, comp.InjectFields)
+		}
+		if comp.ConstructorName != "Inject_"+comp.StructName || !comp.IsPointer || len(comp.Params) != len(fields) {
+			t.Errorf("unexpected generated constructor for %s: %s with %d params", comp.StructName, comp.ConstructorName, len(comp.Params))
+		}
+	}
+}
+
+func TestParsePackagesConfigInstance(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_config_instance", Options{})
+
+	for _, comp := range genCtx.Components {
+		switch {
+		case comp.StructName == "StorageConfig":
+			if !comp.Hidden {
+				t.Error("expected the shared StorageConfig instance to be hidden")
+			}
+			continue
+		case comp.StructName == "MetricsConfig":
+			t.Error("expected no shared instance for MetricsConfig, it injects no fields")
+			continue
+		case comp.StructName == "Report":
+			for _, param := range comp.Params {
+				if len(param.Providers) != 1 || param.Providers[0].StructName != "Settings" {
+					t.Errorf("expected Report's %s to be provided by Settings only, got %+v", param.Name, param.Providers)
+				}
+			}
+			continue
+		case comp.ConfigStructName == "MetricsConfig":
+			if comp.ConfigInstance {
+				t.Errorf("expected %s to be called on a zero MetricsConfig", comp.ConfigMethodName)
+			}
+			continue
+		case comp.ConfigStructName == "":
+			continue
+		}
+		if !comp.ConfigInstance || len(comp.Params) == 0 {
+			t.Fatalf("expected %s to be called on the shared instance, got %+v", comp.ConfigMethodName, comp.Params)
+		}
+		receiver := comp.Params[0]
+		if receiver.Source != engine.SourceComponent || receiver.Providers[0].StructName != "StorageConfig" {
+			t.Errorf("expected the receiver of %s to be provided by StorageConfig, got %+v", comp.ConfigMethodName, receiver)
+		}
+	}
+}
+
+func TestParsePackagesConstructorDirective(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_constructor_directive", Options{})
+
+	for _, comp := range genCtx.Components {
+		switch comp.StructName {
+		case "AuthPlugin":
+			if !comp.IsPrimary || comp.Scope != ScopePrototype || comp.Order != 2 {
+				t.Errorf("expected AuthPlugin to merge tag and comment, got primary=%v scope=%s order=%d", comp.IsPrimary, comp.Scope, comp.Order)
+			}
+		case "AuditPlugin":
+			if comp.Order != 1 || comp.Key != "audit" {
+				t.Errorf("expected AuditPlugin to have order 1 and key 'audit', got order=%d key=%s", comp.Order, comp.Key)
+			}
+		}
+	}
+}
+
+func TestParsePackagesDirectiveForms(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_directive_forms", Options{})
+
+	found := make(map[string]*engine.ComponentMetadata)
+	for _, comp := range genCtx.Components {
+		found[comp.StructName] = comp
+	}
+
+	if mail := found["MailNotifier"]; mail == nil || !mail.IsPrimary || mail.Order != 2 {
+		t.Errorf("expected MailNotifier to merge both directive lines, got %+v", mail)
+	}
+	if sms := found["SmsNotifier"]; sms == nil || sms.Order != 1 {
+		t.Errorf("expected the spaced form to set the order of SmsNotifier, got %+v", sms)
+	}
+	if clock := found["Clock"]; clock == nil || clock.Scope != ScopePrototype {
+		t.Errorf("expected NewClock to be a prototype provider, got %+v", clock)
+	}
+	if cache := found["Cache"]; cache == nil || cache.Scope != ScopeLazy {
+		t.Errorf("expected the constructor directive to make Cache lazy, got %+v", cache)
+	}
+	if _, ok := found["Broadcaster"]; ok {
+		t.Error("expected the misspelled provider directive to be ignored")
+	}
+}
+
+func TestParsePackagesProviderFuncs(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_provider", Options{})
+
+	providers := make(map[string]*engine.ComponentMetadata)
+	for _, comp := range genCtx.Components {
+		if comp.ProviderPackagePath != "" {
+			providers[comp.ConstructorName] = comp
+		}
+	}
+
+	if len(providers) != 2 {
+		t.Fatalf("expected 2 provider funcs, got %d", len(providers))
+	}
+	if logger := providers["NewLogger"]; logger == nil || logger.StructName != "Logger" || logger.PackagePath != "log/slog" || logger.ProviderPackageName != "main" {
+		t.Errorf("unexpected metadata for NewLogger: %+v", logger)
+	}
+	if client := providers["NewHTTPClient"]; client == nil || client.Scope != ScopePrototype || client.StructName != "Client" {
+		t.Errorf("unexpected metadata for NewHTTPClient: %+v", client)
+	}
+}
+
+func TestParsePackagesDecoratorChain(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_decorator", Options{})
+
+	byConstructor := make(map[string]*engine.ComponentMetadata)
+	for _, comp := range genCtx.Components {
+		byConstructor[comp.ConstructorName] = comp
+	}
+
+	const undecorated = "undecorated:github.com/soner3/flora/internal/scanner/testdata/happy_decorator.UserRepository"
+	if q := byConstructor["NewPostgresRepository"].Qualifier; q != undecorated {
+		t.Errorf("expected the decorated repository to be named %q, got %q", undecorated, q)
+	}
+	if q := byConstructor["WithRetries"].ParamQualifiers["next"]; q != undecorated {
+		t.Errorf("expected the first decorator to wrap the original, got %q", q)
+	}
+	if !byConstructor["NewPostgresRepository"].Hidden || !byConstructor["WithRetries"].Hidden || byConstructor["NewMetricsRepository"].Hidden {
+		t.Error("expected only the last decorator of the chain to be exposed")
+	}
+	if q := byConstructor["NewMetricsRepository"].ParamQualifiers["next"]; q != byConstructor["WithRetries"].Qualifier || q == "" {
+		t.Errorf("expected the last decorator to wrap the first one, got %q", q)
+	}
+
+	repo := byConstructor["NewService"].Params[0]
+	if len(repo.Providers) != 1 || repo.Providers[0].ConstructorName != "NewMetricsRepository" {
+		t.Errorf("expected consumers to get the last decorator, got %+v", repo.Providers)
+	}
+}
+
+func TestParsePackagesDecoratorsOfSameName(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_decorator_packages", Options{})
+
+	qualifiers := make(map[string]bool)
+	for _, comp := range genCtx.Components {
+		if comp.Qualifier == "" {
+			continue
+		}
+		if qualifiers[comp.Qualifier] {
+			t.Errorf("expected unique internal qualifiers, got %q twice", comp.Qualifier)
+		}
+		qualifiers[comp.Qualifier] = true
+	}
+
+	for _, comp := range genCtx.Components {
+		if comp.StructName != "Service" {
+			continue
+		}
+		expected := []string{"redis.WithTracing", "httpc.WithTimeout"}
+		for i, param := range comp.Params {
+			if len(param.Providers) != 1 || param.Providers[0].PackageName+"."+param.Providers[0].ConstructorName != expected[i] {
+				t.Errorf("expected parameter %d to be provided by %s, got %+v", i, expected[i], param.Providers)
+			}
+		}
+	}
+}
+
+func TestParsePackagesModuleClosures(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_modules", Options{})
+
+	closures := make(map[string][]string)
+	for _, module := range genCtx.Modules {
+		for _, comp := range module.Components {
+			closures[module.Name] = append(closures[module.Name], comp.StructName)
+		}
+		slices.Sort(closures[module.Name])
+	}
+
+	expected := map[string][]string{
+		"api":    {"ConsoleLogger", "HttpServer", "Repository"},
+		"jobs":   {"ConsoleLogger", "Mailer", "Repository", "Worker"},
+		"worker": {"ConsoleLogger", "Mailer", "Repository", "Worker"},
+	}
+	if !maps.EqualFunc(closures, expected, slices.Equal) {
+		t.Errorf("expected module closures %v, got %v", expected, closures)
+	}
+}
+
+func TestParsePackagesHiddenComponents(t *testing.T) {
+	testcases := []struct {
+		name   string
+		expose string
+		hidden []string
+	}{
+		{name: "TestExposeAll", expose: ExposeAll, hidden: []string{"Metrics"}},
+		{name: "TestExposeRootsOnly", expose: ExposeRootsOnly, hidden: []string{"Metrics", "RedisClient"}},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			opts := Options{Expose: tc.expose}
+			genCtx := mustParse(t, "testdata/happy_expose", opts)
+
+			var hidden []string
+			for _, comp := range genCtx.Components {
+				if comp.Hidden {
+					hidden = append(hidden, comp.StructName)
+				}
+			}
+			slices.Sort(hidden)
+
+			if !slices.Equal(hidden, tc.hidden) {
+				t.Errorf("expected hidden components %v, got %v", tc.hidden, hidden)
+			}
+		})
+	}
+}
+
+func TestParsePackagesReportsAllErrors(t *testing.T) {
+	testcases := []struct {
+		name         string
+		testdataPath string
+		expErrs      []error
+	}{
+		{
+			name:         "TestInvalidComponents",
+			testdataPath: "testdata/err_multiple_components",
+			expErrs:      []error{ErrProviderFuncNotFound, ErrInvalidMetadata},
+		},
+		{
+			name:         "TestInvalidBindings",
+			testdataPath: "testdata/err_multiple_bindings",
+			expErrs:      []error{ErrUnknownQualifier, ErrNoImplementation, ErrDuplicateKey},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			packages, err := ScanPackages(tc.testdataPath, Options{})
+			if err != nil {
+				t.Fatalf("ScanPackages failed: %v", err)
+			}
+
+			_, err = ParsePackages(packages, Options{})
+			for _, expErr := range tc.expErrs {
+				if !errors.Is(err, expErr) {
+					t.Errorf("expected error %v in %v", expErr, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePackagesSuggestions(t *testing.T) {
+	testcases := []struct {
+		name         string
+		testdataPath string
+		expErr       error
+		expected     []string
+		unexpected   []string
+	}{
+		{
+			name:         "TestConstructorTypo",
+			testdataPath: "testdata/err_constructor_typo",
+			expErr:       ErrProviderFuncNotFound,
+			expected:     []string{"did you mean 'NewUserSvc'?", "did you mean 'Newcache'?"},
+		},
+		{
+			name:         "TestNearMissImplementers",
+			testdataPath: "testdata/err_near_miss",
+			expErr:       ErrNoImplementation,
+			expected: []string{
+				"component 'MemStore' in package 'main' lacks 'Put'",
+				"component 'DiskStore' in package 'main' lacks 'Put' (wrong signature)",
+			},
+			unexpected: []string{"'Clock'", "'Service'"},
+		},
+		{
+			name:         "TestConfigurationMethodTag",
+			testdataPath: "testdata/err_config_scope",
+			expErr:       ErrInvalidMetadata,
+			expected:     []string{"component 'BadConfig.ProvideFloat' in package 'errconfigscope'"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			packages, err := ScanPackages(tc.testdataPath, Options{})
+			if err != nil {
+				t.Fatalf("ScanPackages failed: %v", err)
+			}
+
+			_, err = ParsePackages(packages, Options{})
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("expected error %v, got %v", tc.expErr, err)
+			}
+			for _, expected := range tc.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("expected %q in %v", expected, err)
+				}
+			}
+			for _, unexpected := range tc.unexpected {
+				if strings.Contains(err.Error(), unexpected) {
+					t.Errorf("expected no %q in %v", unexpected, err)
+				}
+			}
+		})
+	}
+}
+
+func TestParsePackagesErrorPositions(t *testing.T) {
+	testcases := []struct {
+		name         string
+		testdataPath string
+		expected     []string
+	}{
+		{
+			name:         "TestStructAndTag",
+			testdataPath: "testdata/err_multiple_components",
+			expected:     []string{"main.go:20:6", "main.go:25:8"},
+		},
+		{
+			name:         "TestParametersAndStruct",
+			testdataPath: "testdata/err_multiple_bindings",
+			expected:     []string{"main.go:50:17", "main.go:50:27", "main.go:39:6"},
+		},
+		{
+			name:         "TestMagicComment",
+			testdataPath: "testdata/err_directive_conflict",
+			expected:     []string{"main.go:24:1"},
+		},
+		{
+			name:         "TestContextParam",
+			testdataPath: "testdata/err_context_lazy",
+			expected:     []string{"main.go:28:20"},
+		},
+		{
+			name:         "TestConfigurationMethods",
+			testdataPath: "testdata/err_multiple_config_methods",
+			expected:     []string{"main.go:24:1", "main.go:27:21"},
+		},
+		{
+			name:         "TestDecoratorChains",
+			testdataPath: "testdata/err_multiple_decorators",
+			expected:     []string{"main.go:23:6", "main.go:26:6"},
+		},
+		{
+			name:         "TestGenericInstantiations",
+			testdataPath: "testdata/err_multiple_generics",
+			expected:     []string{"main.go:24:6", "main.go:30:6"},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			packages, err := ScanPackages(tc.testdataPath, Options{})
+			if err != nil {
+				t.Fatalf("ScanPackages failed: %v", err)
+			}
+
+			_, err = ParsePackages(packages, Options{})
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+
+			var positions []string
+			for _, e := range joinedErrors(err) {
+				var floraErr *errs.FloraError
+				if !errors.As(e, &floraErr) {
+					t.Fatalf("expected a FloraError, got %v", e)
+				}
+				pos := floraErr.Position
+				if !strings.HasPrefix(e.Error(), pos.String()+": ") {
+					t.Errorf("expected %q to start with its position %s", e.Error(), pos)
+				}
+				positions = append(positions, fmt.Sprintf("%s:%d:%d", filepath.Base(pos.Filename), pos.Line, pos.Column))
+			}
+
+			for _, expected := range tc.expected {
+				if !slices.Contains(positions, expected) {
+					t.Errorf("expected an error at %s, got %v", expected, positions)
+				}
+			}
+		})
+	}
+}
+
+// joinedErrors flattens the errors joined into err, also those joined in turn
+func joinedErrors(err error) []error {
+	multi, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []error{err}
+	}
+	var result []error
+	for _, inner := range multi.Unwrap() {
+		result = append(result, joinedErrors(inner)...)
+	}
+	return result
+}
+
+func TestParsePackagesMapKeys(t *testing.T) {
+	genCtx := mustParse(t, "testdata/happy_map", Options{})
+
+	if len(genCtx.MapBindings) != 1 {
+		t.Fatalf("expected 1 map binding, got %d", len(genCtx.MapBindings))
+	}
+
+	var keys []string
+	for _, impl := range genCtx.MapBindings[0].Implementations {
+		keys = append(keys, impl.Key)
+	}
+	slices.Sort(keys)
+
+	if !slices.Equal(keys, []string{"DeleteHandler", "create"}) {
+		t.Errorf("expected keys [DeleteHandler create], got %v", keys)
+	}
+}
+
+// mustParse scans and parses the testdata directory and fails the test on any error
+func mustParse(t *testing.T, dir string, opts Options) *engine.GeneratorContext {
+	t.Helper()
+
+	packages, err := ScanPackages(dir, opts)
+	if err != nil {
+		t.Fatalf("ScanPackages failed: %v", err)
+	}
+
+	genCtx, err := ParsePackages(packages, opts)
+	if err != nil {
+		t.Fatalf("ParsePackages failed: %v", err)
+	}
+	return genCtx
+}
@@ -18,7 +18,11 @@ package scanner
 import (
 	"errors"
 	"fmt"
+	"go/token"
 	"log/slog"
+	"os"
+	"strconv"
+	"strings"
 
 	"github.com/soner3/flora/internal/errs"
 	"golang.org/x/tools/go/packages"
@@ -29,10 +33,10 @@ var (
 	ErrCompile      = errors.New("compile error in package")
 )
 
-func ScanPackages(rootDir string) ([]*packages.Package, error) {
+func ScanPackages(rootDir string, opts Options) ([]*packages.Package, error) {
 	log := slog.With("pkg", "scanner")
 
-	log.Debug("Scanning packages", "rootDir", rootDir)
+	log.Debug("Scanning packages", "rootDir", rootDir, "tags", opts.Tags, "goos", opts.GOOS, "goarch", opts.GOARCH)
 
 	cfg := &packages.Config{
 		Mode: packages.NeedName |
@@ -40,7 +44,9 @@ func ScanPackages(rootDir string) ([]*packages.Package, error) {
 			packages.NeedSyntax |
 			packages.NeedTypes |
 			packages.NeedTypesInfo,
-		Dir: rootDir,
+		Dir:        rootDir,
+		BuildFlags: buildFlags(opts),
+		Env:        buildEnv(opts),
 	}
 
 	log.Debug("Loading packages via packages.Load...")
@@ -51,16 +57,82 @@ func ScanPackages(rootDir string) ([]*packages.Package, error) {
 	}
 
 	var validPkgs []*packages.Package
+	var compileErrs []error
 	for _, pkg := range pkgs {
-		if len(pkg.Errors) > 0 {
-			chainErr := fmt.Errorf("%w: %w", ErrCompile, pkg.Errors[0])
-			return nil, errs.Wrap(chainErr, "package ID: %s", pkg.ID)
+		for _, pkgErr := range pkg.Errors {
+			pos, ok := compilePosition(pkgErr.Pos)
+			if !ok {
+				chainErr := fmt.Errorf("%w: %w", ErrCompile, pkgErr)
+				compileErrs = append(compileErrs, errs.Wrap(chainErr, "package ID: %s", pkg.ID))
+				continue
+			}
+			chainErr := fmt.Errorf("%w: %s", ErrCompile, pkgErr.Msg)
+			compileErrs = append(compileErrs, errs.WrapAt(pos, chainErr, "package ID: %s", pkg.ID))
 		}
 
 		validPkgs = append(validPkgs, pkg)
 	}
+	if len(compileErrs) > 0 {
+		return nil, errors.Join(compileErrs...)
+	}
 
 	log.Debug("Successfully filtered packages", "total_loaded", len(pkgs), "valid_count", len(validPkgs))
 
 	return validPkgs, nil
 }
+
+// compilePosition parses the 'file:line:col' or 'file:line' position of a package error
+func compilePosition(pos string) (token.Position, bool) {
+	var position token.Position
+	rest, last, ok := cutLast(pos)
+	if !ok {
+		return position, false
+	}
+	n, err := strconv.Atoi(last)
+	if err != nil {
+		return position, false
+	}
+	position.Line = n
+
+	if file, line, ok := cutLast(rest); ok {
+		if n, err := strconv.Atoi(line); err == nil {
+			position.Filename, position.Line, position.Column = file, n, position.Line
+			return position, true
+		}
+	}
+	position.Filename = rest
+	return position, true
+}
+
+// cutLast slices s around the last ':'
+func cutLast(s string) (before, after string, found bool) {
+	if i := strings.LastIndex(s, ":"); i >= 0 {
+		return s[:i], s[i+1:], true
+	}
+	return s, "", false
+}
+
+// buildFlags returns the go build flags matching the scan options
+func buildFlags(opts Options) []string {
+	if len(opts.Tags) == 0 {
+		return nil
+	}
+	return []string{"-tags=" + strings.Join(opts.Tags, ",")}
+}
+
+// buildEnv returns the environment for the go command, or nil to inherit
+// the current environment if no target platform is set
+func buildEnv(opts Options) []string {
+	if opts.GOOS == "" && opts.GOARCH == "" {
+		return nil
+	}
+
+	env := os.Environ()
+	if opts.GOOS != "" {
+		env = append(env, "GOOS="+opts.GOOS)
+	}
+	if opts.GOARCH != "" {
+		env = append(env, "GOARCH="+opts.GOARCH)
+	}
+	return env
+}
@@ -17,6 +17,7 @@ package scanner
 
 import (
 	"errors"
+	"go/token"
 	"testing"
 )
 
@@ -24,6 +25,7 @@ func TestScanPackages(t *testing.T) {
 	testcases := []struct {
 		name     string
 		path     string
+		opts     Options
 		expected int
 		expErr   error
 	}{
@@ -33,6 +35,13 @@ func TestScanPackages(t *testing.T) {
 			expected: 1,
 			expErr:   nil,
 		},
+		{
+			name:     "TestScanPackagesWithBuildOptions",
+			path:     "testdata/happy_tags",
+			opts:     Options{Tags: []string{"integration"}, GOOS: "windows", GOARCH: "arm64"},
+			expected: 1,
+			expErr:   nil,
+		},
 		{
 			name:     "TestScanPackagesFailedScan",
 			path:     "testdata/foo",
@@ -49,7 +58,7 @@ func TestScanPackages(t *testing.T) {
 
 	for _, tc := range testcases {
 		t.Run(tc.name, func(t *testing.T) {
-			packages, err := ScanPackages(tc.path)
+			packages, err := ScanPackages(tc.path, tc.opts)
 
 			if tc.expErr != nil {
 				if err == nil {
@@ -78,3 +87,37 @@ func TestScanPackages(t *testing.T) {
 		})
 	}
 }
+
+func TestScanPackagesReportsAllErrors(t *testing.T) {
+	_, err := ScanPackages("testdata/sad_multi", Options{})
+	if !errors.Is(err, ErrCompile) {
+		t.Fatalf("expected error %v but got %v", ErrCompile, err)
+	}
+
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok || len(joined.Unwrap()) != 2 {
+		t.Errorf("expected the errors of both packages, got %v", err)
+	}
+}
+
+func TestCompilePosition(t *testing.T) {
+	testcases := []struct {
+		pos      string
+		expected token.Position
+		ok       bool
+	}{
+		{pos: "/src/main.go:12:6", expected: token.Position{Filename: "/src/main.go", Line: 12, Column: 6}, ok: true},
+		{pos: "/src/main.go:12", expected: token.Position{Filename: "/src/main.go", Line: 12}, ok: true},
+		{pos: "-", ok: false},
+		{pos: "", ok: false},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.pos, func(t *testing.T) {
+			pos, ok := compilePosition(tc.pos)
+			if ok != tc.ok || (ok && pos != tc.expected) {
+				t.Errorf("expected %v (%t), got %v (%t)", tc.expected, tc.ok, pos, ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package scanner
+
+import (
+	"fmt"
+	"go/types"
+	"strings"
+)
+
+// didYouMean returns a " (did you mean 'x'?)" hint naming the candidate closest
+// to name, or an empty string if none is close enough to be a likely typo.
+// Case is ignored and longer names may differ in more characters.
+func didYouMean(name string, candidates []string) string {
+	best, bestDist := "", 0
+	for _, candidate := range candidates {
+		if candidate == name {
+			continue
+		}
+		dist := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if dist > max(2, max(len(name), len(candidate))/3) || dist >= len(candidate) {
+			continue
+		}
+		if best == "" || dist < bestDist {
+			best, bestDist = candidate, dist
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean '%s'?)", best)
+}
+
+// editDistance returns the Levenshtein distance between a and b
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
+
+// funcNames returns the names of the functions declared in the package scope
+func funcNames(pkg *types.Package) []string {
+	var names []string
+	for _, name := range pkg.Scope().Names() {
+		if _, ok := pkg.Scope().Lookup(name).(*types.Func); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// nearMisses describes the components that implement some, but not all methods
+// of the interface, together with the methods each one lacks. It returns an
+// empty string if there are none.
+func nearMisses(components []*scannedComponent, iface types.Type) string {
+	ifaceType, ok := iface.Underlying().(*types.Interface)
+	if !ok {
+		return ""
+	}
+
+	var misses []string
+	for _, comp := range components {
+		var lacking []string
+		absent := 0
+		for method := range ifaceType.Methods() {
+			single := types.NewInterfaceType([]*types.Func{method}, nil).Complete()
+			missing, wrongType := types.MissingMethod(comp.PtrType, single, true)
+			switch {
+			case missing == nil:
+			case wrongType:
+				lacking = append(lacking, fmt.Sprintf("'%s' (wrong signature)", missing.Name()))
+			default:
+				lacking = append(lacking, fmt.Sprintf("'%s'", missing.Name()))
+				absent++
+			}
+		}
+		// Components without a single matching method are not worth mentioning
+		if len(lacking) == 0 || absent == ifaceType.NumMethods() {
+			continue
+		}
+		misses = append(misses, fmt.Sprintf("component '%s' in package '%s' lacks %s",
+			comp.Metadata.StructName, comp.Metadata.PackageName, strings.Join(lacking, ", ")))
+	}
+
+	if len(misses) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (near misses: %s)", strings.Join(misses, "; "))
+}
@@ -0,0 +1,42 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package scanner
+
+import "testing"
+
+func TestDidYouMean(t *testing.T) {
+	candidates := []string{"primary", "prototype", "scope"}
+
+	testcases := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "TestMissingLetter", input: "primry", expected: " (did you mean 'primary'?)"},
+		{name: "TestSwappedLetters", input: "scpoe", expected: " (did you mean 'scope'?)"},
+		{name: "TestTooFar", input: "constructor", expected: ""},
+		{name: "TestShortInput", input: "x", expected: ""},
+		{name: "TestCase", input: "Primary", expected: " (did you mean 'primary'?)"},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := didYouMean(tc.input, candidates); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
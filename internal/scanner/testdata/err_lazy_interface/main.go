@@ -0,0 +1,37 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import "github.com/soner3/flora"
+
+type Predictor interface {
+	Predict() float64
+}
+
+type ModelClient struct {
+	flora.Component `flora:"scope=lazy"`
+}
+
+func NewModelClient() *ModelClient      { return nil }
+func (c *ModelClient) Predict() float64 { return 0 }
+
+type Recommender struct {
+	flora.Component
+}
+
+func NewRecommender(p Predictor) *Recommender { return nil }
+
+func main() {}
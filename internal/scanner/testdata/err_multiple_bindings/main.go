@@ -0,0 +1,52 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import "github.com/soner3/flora"
+
+type Store interface {
+	Save()
+}
+
+type Cache interface {
+	Get()
+}
+
+type Command interface {
+	Run()
+}
+
+type Deploy struct {
+	flora.Component `flora:"key=deploy"`
+}
+
+func NewDeploy() *Deploy { return &Deploy{} }
+func (d *Deploy) Run()   {}
+
+type Rollback struct {
+	flora.Component `flora:"key=deploy"`
+}
+
+func NewRollback() *Rollback { return &Rollback{} }
+func (r *Rollback) Run()     {}
+
+type Service struct {
+	flora.Component `flora:"qualifier=db:primaryDB"`
+}
+
+func NewService(db Store, cache Cache, commands map[string]Command) *Service { return &Service{} }
+
+func main() {}
@@ -0,0 +1,38 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import "github.com/soner3/flora"
+
+type Repository[T any] struct {
+	flora.Component
+}
+
+func NewRepository() *Repository[int] { return nil }
+
+type Cache[T any] struct {
+	flora.Component
+}
+
+func NewCache() *Cache[string] { return nil }
+
+type App struct {
+	flora.Component
+}
+
+func NewApp(repo *Repository[int], cache *Cache[string]) *App { return nil }
+
+func main() {}
@@ -0,0 +1,52 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import "github.com/soner3/flora"
+
+type Store interface {
+	Get(key string) string
+	Put(key, value string)
+}
+
+type MemStore struct {
+	flora.Component
+}
+
+func NewMemStore() *MemStore              { return &MemStore{} }
+func (m *MemStore) Get(key string) string { return "" }
+
+type DiskStore struct {
+	flora.Component
+}
+
+func NewDiskStore() *DiskStore                 { return &DiskStore{} }
+func (d *DiskStore) Get(key string) string     { return "" }
+func (d *DiskStore) Put(key string, value int) {}
+
+type Clock struct {
+	flora.Component
+}
+
+func NewClock() *Clock { return &Clock{} }
+
+type Service struct {
+	flora.Component
+}
+
+func NewService(store Store, clock *Clock) *Service { return &Service{} }
+
+func main() {}
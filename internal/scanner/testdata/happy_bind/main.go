@@ -0,0 +1,62 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/soner3/flora"
+)
+
+type Sender interface {
+	Send(msg string) error
+}
+
+type Router struct {
+	flora.Component `flora:"bind=http.Handler"`
+}
+
+func NewRouter() *Router { return &Router{} }
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {}
+
+type MailSender struct {
+	flora.Component `flora:"bind=Sender"`
+}
+
+func NewMailSender() *MailSender { return &MailSender{} }
+
+func (s *MailSender) Send(msg string) error { return nil }
+
+type AuditLog struct {
+	flora.Component `flora:"nobind=main.Sender,nobind=fmt.Stringer"`
+}
+
+func NewAuditLog() *AuditLog { return &AuditLog{} }
+
+func (a *AuditLog) Send(msg string) error { return nil }
+func (a *AuditLog) String() string        { return "audit" }
+
+type Service struct {
+	flora.Component
+}
+
+func NewService(s Sender, audit *AuditLog) *Service { return &Service{} }
+
+var _ fmt.Stringer = (*AuditLog)(nil)
+
+func main() {}
@@ -0,0 +1,68 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happyconditional
+
+import "github.com/soner3/flora"
+
+type Tracer interface {
+	Trace(msg string)
+}
+
+type NoopTracer struct {
+	flora.Component `flora:"conditional=missing"`
+}
+
+func NewNoopTracer() *NoopTracer       { return nil }
+func (t *NoopTracer) Trace(msg string) {}
+
+type AppTracer struct {
+	flora.Component
+}
+
+func NewAppTracer() *AppTracer        { return nil }
+func (t *AppTracer) Trace(msg string) {}
+
+type Logger struct{}
+
+type Writer interface {
+	Write(p []byte) (int, error)
+}
+
+type DefaultsConfig struct {
+	flora.Configuration
+}
+
+// flora:conditional=missing
+func (c *DefaultsConfig) ProvideLogger(w Writer) *Logger { return nil }
+
+// flora:conditional=missing
+func (c *DefaultsConfig) ProvideClock() Clock { return nil }
+
+type Clock interface {
+	Now() int64
+}
+
+type AppConfig struct {
+	flora.Configuration
+}
+
+func (c *AppConfig) ProvideLogger() *Logger { return nil }
+
+type Service struct {
+	flora.Component
+}
+
+func NewService(t Tracer, l *Logger, c Clock) *Service { return nil }
@@ -0,0 +1,67 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import "github.com/soner3/flora"
+
+type Settings struct {
+	flora.Component
+	DSN string
+}
+
+func NewSettings() *Settings { return &Settings{DSN: "postgres://"} }
+
+// Describer has an unexported method so the Configurations do not provide it
+type Describer interface {
+	describe() string
+}
+
+func (s *Settings) describe() string { return s.DSN }
+
+type DB struct{}
+
+type Cache struct{}
+
+type StorageConfig struct {
+	flora.Configuration
+	settings *Settings
+}
+
+func NewStorageConfig(s *Settings) *StorageConfig { return &StorageConfig{settings: s} }
+
+func (c *StorageConfig) ProvideDB() (*DB, error) { return &DB{}, nil }
+
+// flora:scope=prototype
+func (c *StorageConfig) ProvideCache(db *DB) *Cache { return &Cache{} }
+
+func (c *StorageConfig) describe() string { return "storage" }
+
+type Metrics struct{}
+
+type MetricsConfig struct {
+	flora.Configuration
+	registry string `flora:"-"`
+}
+
+func (c *MetricsConfig) ProvideMetrics() *Metrics { return &Metrics{} }
+
+type Report struct {
+	flora.Component
+}
+
+func NewReport(d Describer, all []Describer, byName map[string]Describer) *Report { return &Report{} }
+
+func main() {}
@@ -0,0 +1,50 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import "github.com/soner3/flora"
+
+type Plugin interface {
+	Run()
+}
+
+type AuthPlugin struct {
+	flora.Component `flora:"primary"`
+}
+
+// NewAuthPlugin builds the plugin that checks credentials.
+//
+// flora:scope=prototype,order=2
+func NewAuthPlugin() *AuthPlugin { return &AuthPlugin{} }
+
+func (p *AuthPlugin) Run() {}
+
+type AuditPlugin struct {
+	flora.Component `flora:"constructor=BuildAuditPlugin,order=1"`
+}
+
+// flora:order=1,key=audit
+func BuildAuditPlugin() *AuditPlugin { return &AuditPlugin{} }
+
+func (p *AuditPlugin) Run() {}
+
+type Runner struct {
+	flora.Component
+}
+
+func NewRunner(plugins []Plugin) *Runner { return &Runner{} }
+
+func main() {}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import "github.com/soner3/flora"
+
+type UserRepository interface {
+	Find(id int) string
+}
+
+type PostgresRepository struct {
+	flora.Component
+}
+
+func NewPostgresRepository() *PostgresRepository { return &PostgresRepository{} }
+
+func (r *PostgresRepository) Find(id int) string { return "" }
+
+type Metrics struct {
+	flora.Component
+}
+
+func NewMetrics() *Metrics { return &Metrics{} }
+
+type MetricsRepository struct {
+	flora.Component `flora:"decorates=UserRepository,order=2"`
+	next            UserRepository
+}
+
+func NewMetricsRepository(next UserRepository, m *Metrics) *MetricsRepository {
+	return &MetricsRepository{next: next}
+}
+
+func (r *MetricsRepository) Find(id int) string { return r.next.Find(id) }
+
+// flora:provider,decorates=UserRepository,order=1
+func WithRetries(next UserRepository) UserRepository { return next }
+
+type Service struct {
+	flora.Component
+}
+
+func NewService(repo UserRepository) *Service { return &Service{} }
+
+func main() {}
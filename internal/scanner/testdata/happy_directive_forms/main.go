@@ -0,0 +1,70 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import "github.com/soner3/flora"
+
+type Notifier interface {
+	Notify(msg string)
+}
+
+type MailNotifier struct{}
+
+func (m *MailNotifier) Notify(msg string) {}
+
+type SmsNotifier struct{}
+
+func (s *SmsNotifier) Notify(msg string) {}
+
+type NotifierConfig struct {
+	flora.Configuration
+}
+
+// ProvideMail sends notifications by mail.
+//
+//flora:primary
+//flora:order=2
+func (c *NotifierConfig) ProvideMail() *MailNotifier { return &MailNotifier{} }
+
+// flora:order=1
+func (c *NotifierConfig) ProvideSms() *SmsNotifier { return &SmsNotifier{} }
+
+type Clock struct{}
+
+//flora:provider
+//flora:scope=prototype
+func NewClock() *Clock { return &Clock{} }
+
+type Cache struct {
+	flora.Component
+}
+
+//flora:scope=lazy
+func NewCache() *Cache { return &Cache{} }
+
+type Broadcaster struct {
+	notifiers []Notifier
+}
+
+//flora:provder
+func NewBroadcaster(notifiers []Notifier) *Broadcaster {
+	return &Broadcaster{notifiers: notifiers}
+}
+
+//flora:primary
+type Ledger struct{}
+
+func main() {}
@@ -0,0 +1,67 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import "github.com/soner3/flora"
+
+type User struct{}
+
+type Order struct{}
+
+type Invoice struct{}
+
+type Store[T any] interface {
+	Save(v T)
+}
+
+type Repository[T any] struct {
+	flora.Component
+}
+
+func NewRepository[T any]() *Repository[T] { return nil }
+func (r *Repository[T]) Save(v T)          {}
+func (r *Repository[T]) Name() string      { return "" }
+
+type InvoiceStore struct {
+	flora.Component
+}
+
+func NewInvoiceStore() *InvoiceStore   { return nil }
+func (s *InvoiceStore) Save(v Invoice) {}
+
+type Service[T any] struct {
+	flora.Component
+}
+
+func NewService[T any](repo *Repository[T]) *Service[T] { return nil }
+
+type App struct {
+	flora.Component
+}
+
+func NewApp(users *Service[User], orders Store[Order], invoices Store[Invoice]) *App { return nil }
+
+type Named interface {
+	Name() string
+}
+
+type Registry struct {
+	flora.Component
+}
+
+func NewRegistry(byName map[string]Named) *Registry { return nil }
+
+func main() {}
@@ -0,0 +1,62 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"sync"
+
+	"github.com/soner3/flora"
+)
+
+type Repository interface {
+	Find(id int) string
+}
+
+type MemoryRepository struct {
+	flora.Component
+}
+
+func NewMemoryRepository() *MemoryRepository { return &MemoryRepository{} }
+
+func (r *MemoryRepository) Find(id int) string { return "" }
+
+type Logger struct {
+	flora.Component
+}
+
+func NewLogger() *Logger { return &Logger{} }
+
+type UserService struct {
+	flora.Component
+	Repo  Repository `flora:"inject"`
+	Log   *Logger    `flora:"inject"`
+	cache map[int]string
+}
+
+type OrderService struct {
+	flora.Component `flora:"autowire"`
+	Repo            Repository
+	Log             *Logger `flora:"-"`
+	count           int
+}
+
+type AuditService struct {
+	flora.Component `flora:"autowire"`
+	sync.Mutex
+	*Logger `flora:"inject"`
+}
+
+func main() {}
@@ -0,0 +1,40 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import "github.com/soner3/flora"
+
+type ModelClient struct {
+	flora.Component `flora:"scope=lazy,name=model"`
+}
+
+func NewModelClient() (*ModelClient, func(), error) { return nil, func() {}, nil }
+
+type Cache struct {
+	flora.Component `flora:"scope=lazy"`
+}
+
+func NewCache() *Cache { return nil }
+
+type Recommender struct {
+	flora.Component `flora:"qualifier=model:model"`
+}
+
+func NewRecommender(model *flora.Lazy[*ModelClient], cache *flora.Lazy[*Cache]) *Recommender {
+	return nil
+}
+
+func main() {}
@@ -0,0 +1,64 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"context"
+
+	"github.com/soner3/flora"
+)
+
+type Server struct {
+	flora.Component
+}
+
+func NewServer(c *Consumer) *Server               { return nil }
+func (s *Server) Start(ctx context.Context) error { return nil }
+func (s *Server) Stop(ctx context.Context) error  { return nil }
+
+type Repository struct {
+	flora.Component
+}
+
+func NewRepository(b Broker) *Repository { return nil }
+
+type Consumer struct {
+	flora.Component
+}
+
+func NewConsumer(r *Repository) *Consumer           { return nil }
+func (c *Consumer) Start(ctx context.Context) error { return nil }
+
+type Broker interface {
+	Publish()
+}
+
+type KafkaBroker struct {
+	flora.Component
+}
+
+func NewKafkaBroker() KafkaBroker                     { return KafkaBroker{} }
+func (b KafkaBroker) Publish()                        {}
+func (b *KafkaBroker) Stop(ctx context.Context) error { return nil }
+
+type Worker struct {
+	flora.Component `flora:"scope=prototype"`
+}
+
+func NewWorker() *Worker                          { return nil }
+func (w *Worker) Start(ctx context.Context) error { return nil }
+
+func main() {}
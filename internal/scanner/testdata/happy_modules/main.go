@@ -0,0 +1,55 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import "github.com/soner3/flora"
+
+type Logger interface {
+	Log(msg string)
+}
+
+type ConsoleLogger struct {
+	flora.Component
+}
+
+func NewConsoleLogger() *ConsoleLogger  { return &ConsoleLogger{} }
+func (l *ConsoleLogger) Log(msg string) {}
+
+type Repository struct {
+	flora.Component
+}
+
+func NewRepository(l Logger) *Repository { return &Repository{} }
+
+type Mailer struct {
+	flora.Component
+}
+
+func NewMailer() *Mailer { return &Mailer{} }
+
+type HttpServer struct {
+	flora.Component `flora:"module=api"`
+}
+
+func NewHttpServer(repo *Repository) *HttpServer { return &HttpServer{} }
+
+type Worker struct {
+	flora.Component `flora:"module=worker|jobs"`
+}
+
+func NewWorker(repo *Repository, m *Mailer) *Worker { return &Worker{} }
+
+func main() {}
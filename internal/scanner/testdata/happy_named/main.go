@@ -0,0 +1,58 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happynamed
+
+import "github.com/soner3/flora"
+
+type DB struct{}
+
+type Notifier interface{ Notify() }
+
+type DatabaseConfig struct {
+	flora.Configuration
+}
+
+// flora:name=readDB
+func (c *DatabaseConfig) ProvideReadDB() *DB { return nil }
+
+// flora:name=writeDB
+func (c *DatabaseConfig) ProvideWriteDB() *DB { return nil }
+
+type MailNotifier struct {
+	flora.Component `flora:"name=mail"`
+}
+
+func NewMailNotifier() *MailNotifier { return nil }
+func (n *MailNotifier) Notify()      {}
+
+type SmsNotifier struct {
+	flora.Component `flora:"name=sms,scope=prototype"`
+}
+
+func NewSmsNotifier() *SmsNotifier { return nil }
+func (n *SmsNotifier) Notify()     {}
+
+type Store struct {
+	flora.Component `flora:"qualifier=reader:readDB,qualifier=writer:writeDB"`
+}
+
+func NewStore(reader *DB, writer *DB) *Store { return nil }
+
+type Alerting struct {
+	flora.Component `flora:"qualifier=n:mail,qualifier=f:sms"`
+}
+
+func NewAlerting(n Notifier, f func() *SmsNotifier) *Alerting { return nil }
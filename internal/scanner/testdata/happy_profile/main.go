@@ -0,0 +1,49 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package happyprofile
+
+import "github.com/soner3/flora"
+
+type UserRepository interface {
+	GetUserName() string
+}
+
+type InMemoryRepository struct {
+	flora.Component `flora:"profile=dev|test"`
+}
+
+func NewInMemoryRepository() *InMemoryRepository  { return nil }
+func (r *InMemoryRepository) GetUserName() string { return "memory" }
+
+type PostgresRepository struct {
+	flora.Component `flora:"profile=prod"`
+}
+
+func NewPostgresRepository() *PostgresRepository  { return nil }
+func (r *PostgresRepository) GetUserName() string { return "postgres" }
+
+type UserService struct {
+	flora.Component
+}
+
+func NewUserService(repo UserRepository) *UserService { return nil }
+
+type DebugConfig struct {
+	flora.Configuration
+}
+
+// flora:profile=!prod
+func (c *DebugConfig) ProvideDebugFlag() bool { return true }
@@ -0,0 +1,42 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/soner3/flora"
+)
+
+// flora:provider
+func NewLogger() *slog.Logger { return slog.Default() }
+
+// NewHTTPClient builds the client shared by all outgoing calls.
+//
+// flora:provider,scope=prototype
+func NewHTTPClient(l *slog.Logger) *http.Client { return &http.Client{} }
+
+// Helper is not a provider.
+func Helper() *http.Client { return nil }
+
+type Service struct {
+	flora.Component
+}
+
+func NewService(l *slog.Logger, client func() *http.Client) *Service { return &Service{} }
+
+func main() {}
@@ -0,0 +1,58 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"context"
+
+	"github.com/soner3/flora"
+)
+
+type Plugin interface {
+	Execute()
+}
+
+type AuditPlugin struct {
+	flora.Component
+}
+
+func NewAuditPlugin() *AuditPlugin { return nil }
+func (p *AuditPlugin) Execute()    {}
+
+type Pool struct {
+	flora.Component
+}
+
+func NewPool() *Pool { return nil }
+
+type UnitOfWork interface {
+	Commit() error
+}
+
+type Tx struct {
+	flora.Component `flora:"scope=request"`
+}
+
+func NewTx(ctx context.Context, pool *Pool) (*Tx, func(), error) { return nil, func() {}, nil }
+func (t *Tx) Commit() error                                      { return nil }
+
+type OrderRepo struct {
+	flora.Component `flora:"scope=request"`
+}
+
+func NewOrderRepo(uow UnitOfWork, plugins []Plugin) *OrderRepo { return nil }
+
+func main() {}
@@ -0,0 +1,101 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package main
+
+import (
+	"context"
+
+	"github.com/soner3/flora"
+)
+
+type DB struct{}
+type Cache struct{}
+
+type AppConfig struct {
+	flora.Configuration
+}
+
+func (c *AppConfig) ProvideDB() *DB       { return &DB{} }
+func (c *AppConfig) ProvideCache() *Cache { return &Cache{} }
+
+type Repository struct {
+	flora.Component
+}
+
+func NewRepository(db *DB) *Repository { return &Repository{} }
+
+type Server struct {
+	flora.Component `flora:"expose"`
+}
+
+func NewServer(repo *Repository) *Server { return &Server{} }
+
+type Job interface {
+	Run()
+}
+
+type Handler interface {
+	Handle()
+}
+
+type CleanupJob struct {
+	flora.Component
+}
+
+func NewCleanupJob() *CleanupJob { return &CleanupJob{} }
+func (j *CleanupJob) Run()       {}
+func (j *CleanupJob) Handle()    {}
+
+type Scheduler struct {
+	flora.Component
+}
+
+func NewScheduler(jobs []Job) *Scheduler             { return &Scheduler{} }
+func (s *Scheduler) Start(ctx context.Context) error { return nil }
+
+type LegacyRouter struct {
+	flora.Component
+}
+
+func NewLegacyRouter(handlers map[string]Handler, h *LegacyHelper) *LegacyRouter {
+	return &LegacyRouter{}
+}
+
+type LegacyHelper struct {
+	flora.Component
+}
+
+func NewLegacyHelper() *LegacyHelper { return &LegacyHelper{} }
+
+type Pool struct {
+	flora.Component
+}
+
+func NewPool() *Pool { return &Pool{} }
+
+// Session is built for every request by NewScope
+type Session struct {
+	flora.Component `flora:"scope=request"`
+}
+
+func NewSession(pool *Pool) *Session { return &Session{} }
+
+type Clock struct{}
+
+// flora:provider
+func NewClock() *Clock { return &Clock{} }
+
+func main() {}
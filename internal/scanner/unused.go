@@ -0,0 +1,88 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package scanner
+
+import (
+	"github.com/soner3/flora/internal/engine"
+	"github.com/soner3/flora/internal/errs"
+)
+
+// UnusedReport lists the parts of the graph no declared root consumes
+type UnusedReport struct {
+	Components    []*engine.ComponentMetadata
+	SliceBindings []*engine.SliceBindingMetadata
+	MapBindings   []*engine.MapBindingMetadata
+}
+
+// isDeclaredRoot reports whether the application reads the component itself:
+// it is exposed, roots a module, binds an interface, starts with the container
+// or is request scoped, as NewScope builds every request scoped component
+func isDeclaredRoot(comp *engine.ComponentMetadata) bool {
+	return comp.Expose || len(comp.Modules) > 0 || len(comp.Binds) > 0 || comp.IsStarter || comp.Scope == ScopeRequest
+}
+
+// FindUnused walks the resolved graph from the declared roots and reports
+// the components, slice bindings and map bindings that are never consumed
+func FindUnused(genCtx *engine.GeneratorContext) (*UnusedReport, error) {
+	var queue []*engine.ComponentMetadata
+	for _, comp := range genCtx.Components {
+		if isDeclaredRoot(comp) {
+			queue = append(queue, comp)
+		}
+	}
+	if len(queue) == 0 && len(genCtx.Components) > 0 {
+		return nil, errs.Wrap(ErrNoRoots, "tag the components your application reads from the container with 'expose'")
+	}
+
+	used := make(map[*engine.ComponentMetadata]bool)
+	collected := make(map[string]map[string]bool)
+	for len(queue) > 0 {
+		comp := queue[0]
+		queue = queue[1:]
+		if used[comp] {
+			continue
+		}
+		used[comp] = true
+		for _, param := range comp.Params {
+			queue = append(queue, param.Providers...)
+			if param.Source == engine.SourceSlice || param.Source == engine.SourceMap {
+				if collected[param.Source] == nil {
+					collected[param.Source] = make(map[string]bool)
+				}
+				collected[param.Source][param.Interface.Key()] = true
+			}
+		}
+	}
+
+	report := &UnusedReport{}
+	for _, comp := range genCtx.Components {
+		if !used[comp] {
+			report.Components = append(report.Components, comp)
+		}
+	}
+	for _, sb := range genCtx.SliceBindings {
+		if !collected[engine.SourceSlice][sb.Interface.Key()] {
+			report.SliceBindings = append(report.SliceBindings, sb)
+		}
+	}
+	for _, mb := range genCtx.MapBindings {
+		if !collected[engine.SourceMap][mb.Interface.Key()] {
+			report.MapBindings = append(report.MapBindings, mb)
+		}
+	}
+
+	return report, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright © 2026 Soner Astan astansoner@gmail.com
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+package scanner
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestFindUnused(t *testing.T) {
+	testcases := []struct {
+		name          string
+		testdataPath  string
+		expUnused     []string
+		expUnusedMaps int
+		expErr        error
+	}{
+		{
+			name:          "TestUnusedComponents",
+			testdataPath:  "testdata/happy_unused",
+			expUnused:     []string{"LegacyHelper", "LegacyRouter", "NewClock", "ProvideCache"},
+			expUnusedMaps: 1,
+		},
+		{
+			name:         "TestNoRoots",
+			testdataPath: "testdata/err_no_roots",
+			expErr:       ErrNoRoots,
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			genCtx := mustParse(t, tc.testdataPath, Options{})
+
+			report, err := FindUnused(genCtx)
+			if !errors.Is(err, tc.expErr) {
+				t.Fatalf("expected error %v, got %v", tc.expErr, err)
+			}
+			if tc.expErr != nil {
+				return
+			}
+
+			var unused []string
+			for _, comp := range report.Components {
+				if comp.Position.Line == 0 {
+					t.Errorf("expected a position for '%s'", comp.StructName)
+				}
+				switch {
+				case comp.ConfigMethodName != "":
+					unused = append(unused, comp.ConfigMethodName)
+				case comp.ProviderPackagePath != "":
+					unused = append(unused, comp.ConstructorName)
+				default:
+					unused = append(unused, comp.StructName)
+				}
+			}
+			slices.Sort(unused)
+
+			if !slices.Equal(unused, tc.expUnused) {
+				t.Errorf("expected unused %v, got %v", tc.expUnused, unused)
+			}
+			if len(report.SliceBindings) != 0 {
+				t.Errorf("expected every slice binding to be used, got %d unused", len(report.SliceBindings))
+			}
+			if len(report.MapBindings) != tc.expUnusedMaps {
+				t.Errorf("expected %d unused map bindings, got %d", tc.expUnusedMaps, len(report.MapBindings))
+			}
+		})
+	}
+}